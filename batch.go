@@ -0,0 +1,91 @@
+package let
+
+import (
+	"context"
+	"time"
+)
+
+// Batch creates a Task that accumulates the values received from `in`
+// and calls `flush` with them once `maxSize` values are accumulated
+// or `maxWait` has passed since the first value of the batch.
+// Run returns when `in` is closed after flushing the rest of the values,
+// or when `flush` returns an error.
+// Stop flushes the rest of the values, but Close drops them.
+// If values are dropped, Run returns [ErrForceClosed] on Close
+// or the error of the context if the context is done.
+func Batch[T any](in <-chan T, maxSize int, maxWait time.Duration, flush func(ctx context.Context, vs []T) error) Task {
+	return BatchWithClock(RealClock, in, maxSize, maxWait, flush)
+}
+
+// BatchWithClock is like [Batch] but `maxWait` is measured by the given Clock.
+func BatchWithClock[T any](c Clock, in <-chan T, maxSize int, maxWait time.Duration, flush func(ctx context.Context, vs []T) error) Task {
+	if maxSize < 1 {
+		panic("maxSize must be larger than 0")
+	}
+
+	var t *task
+	t = NewGraceful(0, func(ctx context.Context) error {
+		timer := c.NewTimer(maxWait)
+		timer.Stop()
+		defer timer.Stop()
+
+		vs := make([]T, 0, maxSize)
+		emit := func() error {
+			timer.Stop()
+			if len(vs) == 0 {
+				return nil
+			}
+
+			err := flush(ctx, vs)
+			vs = make([]T, 0, maxSize)
+			return err
+		}
+
+		drop := func() error {
+			if len(vs) == 0 {
+				return nil
+			}
+			if t.forced.Load() {
+				return ErrForceClosed
+			}
+			if err := stoppingCause(ctx); err != nil {
+				return err
+			}
+			return ctx.Err()
+		}
+
+		stopping := Stopping(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return drop()
+			case <-stopping:
+				if !stoppedGracefully(ctx) {
+					return drop()
+				}
+				return emit()
+			case v, ok := <-in:
+				if !ok {
+					return emit()
+				}
+
+				vs = append(vs, v)
+				if len(vs) == 1 {
+					timer.Reset(maxWait)
+				}
+				if len(vs) < maxSize {
+					continue
+				}
+				if err := emit(); err != nil {
+					return err
+				}
+			case <-timer.C():
+				if err := emit(); err != nil {
+					return err
+				}
+			}
+		}
+	}).(*task)
+
+	return t
+}
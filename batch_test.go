@@ -0,0 +1,146 @@
+package let_test
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	t.Run("flushes by size", func(t *testing.T) {
+		in := make(chan int)
+		c := make(chan []int, 3)
+		task := let.Batch(in, 2, time.Hour, func(ctx context.Context, vs []int) error {
+			c <- vs
+			return nil
+		})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		for i := range 5 {
+			in <- i
+		}
+		require.Equal(t, []int{0, 1}, <-c)
+		require.Equal(t, []int{2, 3}, <-c)
+
+		close(in)
+		require.NoError(t, <-done)
+		require.Equal(t, []int{4}, <-c)
+	})
+	t.Run("flushes by age", func(t *testing.T) {
+		clock := let.NewFakeClock(time.Now())
+
+		in := make(chan int)
+		c := make(chan []int, 1)
+		task := let.BatchWithClock(clock, in, 10, time.Second, func(ctx context.Context, vs []int) error {
+			c <- vs
+			return nil
+		})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		in <- 1
+		in <- 2
+
+		err := clock.BlockUntil(t.Context(), 1)
+		require.NoError(t, err)
+		clock.Advance(time.Second)
+		require.Equal(t, []int{1, 2}, <-c)
+
+		close(in)
+		require.NoError(t, <-done)
+	})
+	t.Run("stop flushes the rest", func(t *testing.T) {
+		in := make(chan int)
+		c := make(chan []int, 1)
+		task := let.Batch(in, 10, time.Hour, func(ctx context.Context, vs []int) error {
+			c <- vs
+			return nil
+		})
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		in <- 1
+		in <- 2
+
+		err := task.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+		require.Equal(t, []int{1, 2}, <-c)
+	})
+	t.Run("close drops the rest", func(t *testing.T) {
+		in := make(chan int)
+		flushed := atomic.Bool{}
+		task := let.Batch(in, 10, time.Hour, func(ctx context.Context, vs []int) error {
+			flushed.Store(true)
+			return nil
+		})
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		in <- 1
+
+		task.Close()
+		require.ErrorIs(t, <-done, let.ErrForceClosed)
+		require.False(t, flushed.Load())
+	})
+	t.Run("context done drops the rest", func(t *testing.T) {
+		in := make(chan int)
+		flushed := atomic.Bool{}
+		task := let.Batch(in, 10, time.Hour, func(ctx context.Context, vs []int) error {
+			flushed.Store(true)
+			return nil
+		})
+		defer let.Halt(task)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan error)
+		go func() { done <- task.Run(ctx) }()
+
+		in <- 1
+
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+		require.False(t, flushed.Load())
+	})
+	t.Run("close without values is not an error", func(t *testing.T) {
+		in := make(chan int)
+		c := make(chan []int)
+		task := let.Batch(in, 1, time.Hour, func(ctx context.Context, vs []int) error {
+			c <- vs
+			return nil
+		})
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		in <- 1
+		require.Equal(t, []int{1}, <-c)
+
+		task.Close()
+		require.NoError(t, <-done)
+	})
+	t.Run("flush error is returned", func(t *testing.T) {
+		in := make(chan int, 1)
+		in <- 1
+
+		task := let.Batch(in, 1, time.Hour, func(ctx context.Context, vs []int) error {
+			return io.EOF
+		})
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, io.EOF)
+	})
+}
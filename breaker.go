@@ -0,0 +1,117 @@
+package let
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type BreakerConfig struct {
+	// Threshold is the number of consecutive failures that opens the breaker.
+	// Defaults to 1.
+	Threshold int
+	// Cooldown is the duration the breaker stays open before it allows a trial run.
+	Cooldown time.Duration
+	// Clock measures the cooldown.
+	// Defaults to [RealClock].
+	Clock Clock
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type breaker struct {
+	Task
+
+	cfg BreakerConfig
+
+	m        sync.Mutex
+	state    breakerState
+	failures int
+	opened   time.Time
+}
+
+// Breaker creates a Task that stops running the given Task after it fails
+// `cfg.Threshold` times in a row.
+// While the breaker is open, Run returns [ErrBreakerOpen] immediately.
+// After `cfg.Cooldown` elapses, a single trial Run is allowed at a time;
+// the breaker closes if the trial succeeds and opens again if it fails.
+// [ErrClosed] and [ErrBusy] returned from the given Task and errors after the context of Run is done
+// are not counted as failures.
+func Breaker(cfg BreakerConfig, t Task) Task {
+	if cfg.Threshold < 1 {
+		cfg.Threshold = 1
+	}
+	cfg.Clock = clockOr(cfg.Clock)
+	return &breaker{Task: t, cfg: cfg}
+}
+
+func (t *breaker) acquire() bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	switch t.state {
+	case breakerClosed:
+		return true
+	case breakerOpen:
+		if t.cfg.Clock.Now().Sub(t.opened) < t.cfg.Cooldown {
+			return false
+		}
+		t.state = breakerHalfOpen
+		return true
+	default:
+		// Trial run is in progress.
+		return false
+	}
+}
+
+func (t *breaker) release(ctx context.Context, err error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if err == nil {
+		t.state = breakerClosed
+		t.failures = 0
+		return
+	}
+	if errors.Is(err, ErrClosed) || errors.Is(err, ErrBusy) || ctx.Err() != nil {
+		// The Task is not run or the caller gave up.
+		if t.state == breakerHalfOpen {
+			// The trial did not happen.
+			t.state = breakerOpen
+		}
+		return
+	}
+
+	t.failures++
+	if t.state == breakerHalfOpen || t.failures >= t.cfg.Threshold {
+		t.state = breakerOpen
+		t.opened = t.cfg.Clock.Now()
+	}
+}
+
+func (t *breaker) Run(ctx context.Context) error {
+	return t.run(ctx, t.Task.Run)
+}
+
+func (t *breaker) TryRun(ctx context.Context) error {
+	return t.run(ctx, func(ctx context.Context) error {
+		return TryRun(ctx, t.Task)
+	})
+}
+
+func (t *breaker) run(ctx context.Context, f func(ctx context.Context) error) error {
+	if !t.acquire() {
+		return ErrBreakerOpen
+	}
+
+	err := f(ctx)
+	t.release(ctx, err)
+	return err
+}
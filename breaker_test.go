@@ -0,0 +1,110 @@
+package let_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		i := 0
+		task := let.Breaker(let.BreakerConfig{Threshold: 2, Cooldown: time.Hour}, let.New(func(ctx context.Context) error {
+			i++
+			return io.EOF
+		}))
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, io.EOF)
+
+		err = task.Run(t.Context())
+		require.ErrorIs(t, err, io.EOF)
+
+		err = task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrBreakerOpen)
+		require.Equal(t, 2, i)
+	})
+	t.Run("success resets the failure count", func(t *testing.T) {
+		errs := []error{io.EOF, nil, io.EOF, io.EOF}
+		task := let.Breaker(let.BreakerConfig{Threshold: 2, Cooldown: time.Hour}, let.New(func(ctx context.Context) error {
+			err := errs[0]
+			errs = errs[1:]
+			return err
+		}))
+		defer let.Halt(task)
+
+		require.ErrorIs(t, task.Run(t.Context()), io.EOF)
+		require.NoError(t, task.Run(t.Context()))
+		require.ErrorIs(t, task.Run(t.Context()), io.EOF)
+		require.ErrorIs(t, task.Run(t.Context()), io.EOF)
+		require.ErrorIs(t, task.Run(t.Context()), let.ErrBreakerOpen)
+	})
+	t.Run("allows trial run after cooldown", func(t *testing.T) {
+		c := let.NewFakeClock(time.Time{})
+
+		var err error = io.EOF
+		task := let.Breaker(let.BreakerConfig{Threshold: 1, Cooldown: time.Second, Clock: c}, let.New(func(ctx context.Context) error {
+			return err
+		}))
+		defer let.Halt(task)
+
+		require.ErrorIs(t, task.Run(t.Context()), io.EOF)
+		require.ErrorIs(t, task.Run(t.Context()), let.ErrBreakerOpen)
+
+		c.Advance(time.Second)
+
+		// Trial fails so the breaker opens again.
+		require.ErrorIs(t, task.Run(t.Context()), io.EOF)
+		require.ErrorIs(t, task.Run(t.Context()), let.ErrBreakerOpen)
+
+		c.Advance(time.Second)
+
+		// Trial succeeds so the breaker closes.
+		err = nil
+		require.NoError(t, task.Run(t.Context()))
+		require.NoError(t, task.Run(t.Context()))
+	})
+	t.Run("canceled run is not a failure", func(t *testing.T) {
+		c := let.NewFakeClock(time.Time{})
+
+		var err error
+		task := let.Breaker(let.BreakerConfig{Threshold: 1, Cooldown: time.Second, Clock: c}, let.New(func(ctx context.Context) error {
+			if err != nil {
+				return err
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+		defer let.Halt(task)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		require.ErrorIs(t, task.Run(ctx), context.Canceled)
+
+		// Canceled while the body runs.
+		ctx, cancel = context.WithCancel(t.Context())
+		go cancel()
+		require.ErrorIs(t, task.Run(ctx), context.Canceled)
+
+		err = io.EOF
+		require.ErrorIs(t, task.Run(t.Context()), io.EOF)
+		require.ErrorIs(t, task.Run(t.Context()), let.ErrBreakerOpen)
+
+		c.Advance(time.Second)
+
+		// Canceled trial does not open the breaker again.
+		err = nil
+		ctx, cancel = context.WithCancel(t.Context())
+		go cancel()
+		require.ErrorIs(t, task.Run(ctx), context.Canceled)
+
+		err = io.EOF
+		require.ErrorIs(t, task.Run(t.Context()), io.EOF)
+	})
+}
@@ -0,0 +1,269 @@
+package let
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock provides the time to time-based Tasks.
+// Use [NewFakeClock] to control the time in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a [time.Timer] created by a [Clock].
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is a [time.Ticker] created by a [Clock].
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// RealClock is a Clock backed by the time package.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+func clockOr(c Clock) Clock {
+	if c == nil {
+		return RealClock
+	}
+	return c
+}
+
+// FakeClock is a Clock whose time only moves by [FakeClock.Advance].
+type FakeClock struct {
+	m      sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+
+	changed chan struct{}
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+
+	c      chan time.Time
+	at     time.Time
+	period time.Duration
+}
+
+// NewFakeClock creates a FakeClock starting at the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	return c.add(d, 0)
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return fakeTicker{c.add(d, d)}
+}
+
+func (c *FakeClock) add(d time.Duration, period time.Duration) *fakeTimer {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	t := &fakeTimer{
+		clock: c,
+
+		c:      make(chan time.Time, 1),
+		at:     c.now.Add(d),
+		period: period,
+	}
+	c.schedule(t)
+	return t
+}
+
+// schedule registers the timer or fires it if it is due.
+// The caller must hold the lock.
+func (c *FakeClock) schedule(t *fakeTimer) {
+	if !t.at.After(c.now) && t.period == 0 {
+		t.fire(c.now)
+		return
+	}
+
+	c.timers = append(c.timers, t)
+	c.notify()
+}
+
+// unschedule removes the timer and reports whether the timer was registered.
+// The caller must hold the lock.
+func (c *FakeClock) unschedule(t *fakeTimer) bool {
+	i := slices.Index(c.timers, t)
+	if i < 0 {
+		return false
+	}
+
+	c.timers = slices.Delete(c.timers, i, i+1)
+	c.notify()
+	return true
+}
+
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// Advance moves the time forward by `d` and fires the timers that are due.
+// A ticker fires at most once per Advance, as a slow receiver of [time.Ticker] does.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.now = c.now.Add(d)
+
+	timers := c.timers
+	c.timers = nil
+	for _, t := range timers {
+		if t.at.After(c.now) {
+			c.timers = append(c.timers, t)
+			continue
+		}
+
+		t.fire(c.now)
+		if t.period > 0 {
+			for !t.at.After(c.now) {
+				t.at = t.at.Add(t.period)
+			}
+			c.timers = append(c.timers, t)
+		}
+	}
+	c.notify()
+}
+
+// BlockUntil blocks until at least `n` timers and tickers are waiting to fire
+// or the context is done.
+// It is useful to ensure a Task is waiting on the clock before [FakeClock.Advance].
+func (c *FakeClock) BlockUntil(ctx context.Context, n int) error {
+	for {
+		c.m.Lock()
+		l := len(c.timers)
+		changed := c.changed
+		c.m.Unlock()
+
+		if l >= n {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (t *fakeTimer) fire(now time.Time) {
+	select {
+	case t.c <- now:
+	default:
+		// Receiver is slow so drop the tick.
+	}
+}
+
+func (t *fakeTimer) drain() {
+	select {
+	case <-t.c:
+	default:
+	}
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.m.Lock()
+	defer t.clock.m.Unlock()
+
+	t.drain()
+	return t.clock.unschedule(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.m.Lock()
+	defer t.clock.m.Unlock()
+
+	t.drain()
+	active := t.clock.unschedule(t)
+	t.at = t.clock.now.Add(d)
+	if t.period > 0 {
+		t.period = d
+	}
+	t.clock.schedule(t)
+
+	return active
+}
+
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	t.fakeTimer.Reset(d)
+}
@@ -0,0 +1,76 @@
+package let_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClock(t *testing.T) {
+	t0 := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("timer fires on advance", func(t *testing.T) {
+		c := let.NewFakeClock(t0)
+		timer := c.NewTimer(time.Second)
+
+		c.Advance(999 * time.Millisecond)
+		require.Empty(t, timer.C())
+
+		c.Advance(time.Millisecond)
+		v := <-timer.C()
+		require.Equal(t, t0.Add(time.Second), v)
+		require.Equal(t, t0.Add(time.Second), c.Now())
+	})
+	t.Run("stopped timer does not fire", func(t *testing.T) {
+		c := let.NewFakeClock(t0)
+		timer := c.NewTimer(time.Second)
+
+		ok := timer.Stop()
+		require.True(t, ok)
+
+		c.Advance(time.Second)
+		require.Empty(t, timer.C())
+
+		ok = timer.Stop()
+		require.False(t, ok)
+	})
+	t.Run("ticker fires repeatedly", func(t *testing.T) {
+		c := let.NewFakeClock(t0)
+		ticker := c.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for i := range 3 {
+			c.Advance(time.Second)
+			v := <-ticker.C()
+			require.Equal(t, t0.Add(time.Duration(i+1)*time.Second), v)
+		}
+	})
+	t.Run("block until timers are registered", func(t *testing.T) {
+		c := let.NewFakeClock(t0)
+		go c.After(time.Second)
+
+		err := c.BlockUntil(t.Context(), 1)
+		require.NoError(t, err)
+	})
+}
+
+func TestSleep(t *testing.T) {
+	t.Run("sleeps for the duration", func(t *testing.T) {
+		c := let.NewFakeClock(time.Time{})
+		task := let.SleepWithClock(c, time.Second)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		c.BlockUntil(t.Context(), 1)
+		c.Advance(time.Second)
+
+		err := <-done
+		require.NoError(t, err)
+	})
+}
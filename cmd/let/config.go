@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type RestartPolicy string
+
+const (
+	RestartNo        RestartPolicy = "no"
+	RestartOnFailure RestartPolicy = "on-failure"
+	RestartAlways    RestartPolicy = "always"
+)
+
+type Process struct {
+	Name      string        `yaml:"-"`
+	Command   string        `yaml:"command"`
+	DependsOn []string      `yaml:"depends_on"`
+	Restart   RestartPolicy `yaml:"restart"`
+}
+
+type Config struct {
+	Processes map[string]*Process `yaml:"processes"`
+}
+
+func ReadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var c *Config
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		c, err = ParseYaml(f)
+	default:
+		c, err = ParseProcfile(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// ParseProcfile parses lines of `name: command`.
+// Empty lines and lines starting with `#` are ignored.
+func ParseProcfile(r io.Reader) (*Config, error) {
+	c := &Config{Processes: map[string]*Process{}}
+
+	s := bufio.NewScanner(r)
+	for i := 1; s.Scan(); i++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, command, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected `name: command`", i)
+		}
+
+		name = strings.TrimSpace(name)
+		if _, ok := c.Processes[name]; ok {
+			return nil, fmt.Errorf("line %d: duplicated process %q", i, name)
+		}
+		c.Processes[name] = &Process{Command: strings.TrimSpace(command)}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return c, c.validate()
+}
+
+func ParseYaml(r io.Reader) (*Config, error) {
+	c := &Config{}
+	if err := yaml.NewDecoder(r).Decode(c); err != nil {
+		return nil, err
+	}
+
+	return c, c.validate()
+}
+
+func (c *Config) validate() error {
+	for name, p := range c.Processes {
+		p.Name = name
+		if p.Command == "" {
+			return fmt.Errorf("process %q: command is empty", name)
+		}
+
+		switch p.Restart {
+		case "":
+			p.Restart = RestartNo
+		case RestartNo, RestartOnFailure, RestartAlways:
+		default:
+			return fmt.Errorf("process %q: unknown restart policy %q", name, p.Restart)
+		}
+
+		for _, d := range p.DependsOn {
+			if _, ok := c.Processes[d]; !ok {
+				return fmt.Errorf("process %q: unknown dependency %q", name, d)
+			}
+		}
+	}
+
+	_, err := c.Ordered()
+	return err
+}
+
+// Ordered returns the processes in the order where dependencies come first.
+// Otherwise, processes are visited by name and
+// their dependencies in the order they are declared.
+func (c *Config) Ordered() ([]*Process, error) {
+	names := make([]string, 0, len(c.Processes))
+	for name := range c.Processes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	ps := make([]*Process, 0, len(names))
+	marks := map[string]int{}
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			return fmt.Errorf("process %q: circular dependency", name)
+		case visited:
+			return nil
+		}
+
+		marks[name] = visiting
+		p := c.Processes[name]
+		for _, d := range p.DependsOn {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+
+		ps = append(ps, p)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return ps, nil
+}
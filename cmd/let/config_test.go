@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcfile(t *testing.T) {
+	t.Run("processes are parsed", func(t *testing.T) {
+		c, err := ParseProcfile(strings.NewReader(`
+# comment
+web: ./web --port 8080
+worker:   ./worker
+`))
+		require.NoError(t, err)
+		require.Len(t, c.Processes, 2)
+		require.Equal(t, "./web --port 8080", c.Processes["web"].Command)
+		require.Equal(t, "./worker", c.Processes["worker"].Command)
+		require.Equal(t, RestartNo, c.Processes["worker"].Restart)
+	})
+	t.Run("line without name is invalid", func(t *testing.T) {
+		_, err := ParseProcfile(strings.NewReader("./web"))
+		require.ErrorContains(t, err, "line 1")
+	})
+}
+
+func TestParseYaml(t *testing.T) {
+	t.Run("dependencies come first", func(t *testing.T) {
+		c, err := ParseYaml(strings.NewReader(`
+processes:
+  api:
+    command: ./api
+    depends_on: [db, cache]
+    restart: on-failure
+  cache:
+    command: ./cache
+  db:
+    command: ./db
+    restart: always
+`))
+		require.NoError(t, err)
+
+		ps, err := c.Ordered()
+		require.NoError(t, err)
+
+		names := []string{}
+		for _, p := range ps {
+			names = append(names, p.Name)
+		}
+		require.Equal(t, []string{"db", "cache", "api"}, names)
+		require.Equal(t, RestartOnFailure, c.Processes["api"].Restart)
+	})
+	t.Run("circular dependency is invalid", func(t *testing.T) {
+		_, err := ParseYaml(strings.NewReader(`
+processes:
+  a:
+    command: ./a
+    depends_on: [b]
+  b:
+    command: ./b
+    depends_on: [a]
+`))
+		require.ErrorContains(t, err, "circular dependency")
+	})
+	t.Run("unknown restart policy is invalid", func(t *testing.T) {
+		_, err := ParseYaml(strings.NewReader(`
+processes:
+  a:
+    command: ./a
+    restart: sometimes
+`))
+		require.ErrorContains(t, err, "unknown restart policy")
+	})
+}
@@ -0,0 +1,93 @@
+// Command let runs processes described in a Procfile or a YAML file
+// with the semantics of [let.NewGroup].
+// A process starts after the processes it depends on are started.
+// If a process fails without being restarted, all the other processes are stopped.
+// A process that exits successfully without being restarted does not stop the others,
+// and let exits once all the processes have exited.
+// The first SIGINT or SIGTERM stops the processes gracefully and
+// the second one kills them.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lesomnus/let"
+)
+
+func main() {
+	path := flag.String("f", "Procfile", "path to a Procfile or a YAML file")
+	no_color := flag.Bool("no-color", false, "disable colored output")
+	flag.Parse()
+
+	if err := run(*path, !*no_color); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(path string, color bool) error {
+	c, err := ReadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	ps, err := c.Ordered()
+	if err != nil {
+		return err
+	}
+
+	width := 0
+	for _, p := range ps {
+		width = max(width, len(p.Name))
+	}
+
+	out := NewOutput(os.Stdout, width, color)
+	procs := map[string]*proc{}
+	r := let.NewGroup()
+	for _, p := range ps {
+		// Dependencies come first so they are already created.
+		deps := []*proc{}
+		for _, d := range p.DependsOn {
+			deps = append(deps, procs[d])
+		}
+
+		procs[p.Name] = newProc(p, out.Writer(p.Name), deps...)
+		r.Go(procs[p.Name])
+	}
+
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	go handleSignals(sigs, r)
+	go func() {
+		for _, p := range procs {
+			<-p.Exited()
+		}
+		r.Stop(context.Background())
+	}()
+
+	go r.Run(context.Background())
+
+	err = r.Wait()
+	if errors.Is(err, let.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// handleSignals stops the Task gracefully on the first signal
+// and closes it on the second one.
+func handleSignals(sigs <-chan os.Signal, t let.Task) {
+	<-sigs
+	go t.Stop(context.Background())
+
+	<-sigs
+	t.Close()
+}
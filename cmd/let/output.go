@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var colors = []int{36, 33, 32, 35, 34, 31}
+
+// Output writes lines of multiple processes prefixed by their names.
+type Output struct {
+	w     io.Writer
+	width int
+	color bool
+
+	m sync.Mutex
+	n int
+}
+
+func NewOutput(w io.Writer, width int, color bool) *Output {
+	return &Output{w: w, width: width, color: color}
+}
+
+// Writer returns a writer whose lines are prefixed by the given name.
+func (o *Output) Writer(name string) io.Writer {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	prefix := fmt.Sprintf("%-*s | ", o.width, name)
+	if o.color {
+		prefix = fmt.Sprintf("\x1b[%dm%s\x1b[0m", colors[o.n%len(colors)], prefix)
+	}
+	o.n++
+
+	return &prefixWriter{o: o, prefix: []byte(prefix)}
+}
+
+func (o *Output) write(prefix []byte, line []byte) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.w.Write(prefix)
+	o.w.Write(line)
+	o.w.Write([]byte{'\n'})
+}
+
+type prefixWriter struct {
+	o      *Output
+	prefix []byte
+
+	buf []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.o.write(w.prefix, w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the remaining incomplete line.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+
+	w.o.write(w.prefix, w.buf)
+	w.buf = nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/lesomnus/let"
+)
+
+// proc runs a process and restarts it according to its restart policy.
+// The process starts after all of its dependencies are started.
+// Run returns the error of the process if it fails without being restarted.
+// If the process finishes successfully without being restarted,
+// Run blocks until the proc is stopped so the other processes keep running.
+type proc struct {
+	let.Task
+
+	p     *Process
+	out   io.Writer
+	deps  []*proc
+	delay time.Duration
+
+	// started is closed when the process is started for the first time.
+	started     chan struct{}
+	mark_start  func()
+	exited      chan struct{}
+	mark_exited func()
+
+	m       sync.Mutex
+	curr    let.Task
+	stopped bool
+}
+
+func newProc(p *Process, out io.Writer, deps ...*proc) *proc {
+	started := make(chan struct{})
+	exited := make(chan struct{})
+	t := &proc{
+		p:     p,
+		out:   out,
+		deps:  deps,
+		delay: time.Second,
+
+		started:     started,
+		mark_start:  sync.OnceFunc(func() { close(started) }),
+		exited:      exited,
+		mark_exited: sync.OnceFunc(func() { close(exited) }),
+	}
+	t.Task = let.New(t.run)
+
+	return t
+}
+
+// Exited returns a channel that is closed when the process exited without being restarted.
+func (t *proc) Exited() <-chan struct{} {
+	return t.exited
+}
+
+func (t *proc) next() let.Task {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.stopped {
+		return nil
+	}
+
+	cmd := exec.Command("sh", "-c", t.p.Command)
+	cmd.Stdout = t.out
+	cmd.Stderr = t.out
+
+	t.curr = let.Command(cmd)
+	return t.curr
+}
+
+func (t *proc) isStopped() bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return t.stopped
+}
+
+func (t *proc) run(ctx context.Context) error {
+	defer t.mark_exited()
+
+	for _, d := range t.deps {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-d.started:
+		}
+	}
+
+	for {
+		c := t.next()
+		if c == nil {
+			return nil
+		}
+
+		fmt.Fprintf(t.out, "started: %s\n", t.p.Command)
+		t.mark_start()
+		err := c.Run(ctx)
+		let.Halt(c)
+		if f, ok := t.out.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+		if errors.Is(err, let.ErrClosed) || t.isStopped() {
+			// The process is stopped by the proc.
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintf(t.out, "exited: %s\n", err)
+		} else {
+			fmt.Fprintf(t.out, "exited\n")
+		}
+
+		restart := false
+		switch t.p.Restart {
+		case RestartAlways:
+			restart = true
+		case RestartOnFailure:
+			restart = err != nil
+		}
+		if !restart {
+			if err != nil {
+				return err
+			}
+
+			// Keep the other processes running.
+			t.mark_exited()
+			<-ctx.Done()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(t.delay):
+		}
+	}
+}
+
+func (t *proc) current() let.Task {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.stopped = true
+	return t.curr
+}
+
+func (t *proc) Stop(ctx context.Context) error {
+	if c := t.current(); c != nil {
+		if err := c.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	return t.Task.Stop(ctx)
+}
+
+func (t *proc) Close() error {
+	if c := t.current(); c != nil {
+		c.Close()
+	}
+	return t.Task.Close()
+}
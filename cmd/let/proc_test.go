@@ -0,0 +1,191 @@
+//go:build unix
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+type syncBuffer struct {
+	m sync.Mutex
+	b bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.b.String()
+}
+
+func TestProc(t *testing.T) {
+	newTestProc := func(p *Process, deps ...*proc) (*proc, *syncBuffer) {
+		out := &syncBuffer{}
+		t := newProc(p, out, deps...)
+		t.delay = time.Millisecond
+		return t, out
+	}
+
+	// counter returns a command that counts its runs
+	// and a function that reads the count.
+	counter := func(t *testing.T, script string) (string, func() int) {
+		f := filepath.Join(t.TempDir(), "count")
+		command := fmt.Sprintf(`n=$(cat %[1]s 2>/dev/null || echo 0); n=$((n+1)); echo $n > %[1]s; %s`, f, script)
+		return command, func() int {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				return 0
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(string(b)))
+			return n
+		}
+	}
+
+	t.Run("failure is returned without restart", func(t *testing.T) {
+		p, _ := newTestProc(&Process{Command: "exit 3", Restart: RestartNo})
+		defer let.Halt(p)
+
+		err := p.Run(t.Context())
+		var exit_err *exec.ExitError
+		require.ErrorAs(t, err, &exit_err)
+		require.Equal(t, 3, exit_err.ExitCode())
+	})
+	t.Run("success without restart keeps running until stop", func(t *testing.T) {
+		p, out := newTestProc(&Process{Command: "true", Restart: RestartNo})
+
+		done := make(chan error)
+		go func() { done <- p.Run(t.Context()) }()
+
+		<-p.Exited()
+		require.Contains(t, out.String(), "exited\n")
+
+		select {
+		case <-done:
+			t.Fatal("run returned before stop")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		err := p.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+	})
+	t.Run("on-failure restarts until success", func(t *testing.T) {
+		command, count := counter(t, `[ $n -ge 3 ]`)
+		p, _ := newTestProc(&Process{Command: command, Restart: RestartOnFailure})
+
+		done := make(chan error)
+		go func() { done <- p.Run(t.Context()) }()
+
+		<-p.Exited()
+		require.Equal(t, 3, count())
+
+		err := p.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+	})
+	t.Run("always restarts on success", func(t *testing.T) {
+		command, count := counter(t, `true`)
+		p, _ := newTestProc(&Process{Command: command, Restart: RestartAlways})
+
+		done := make(chan error)
+		go func() { done <- p.Run(t.Context()) }()
+
+		require.Eventually(t, func() bool {
+			return count() >= 3
+		}, 5*time.Second, time.Millisecond)
+
+		err := p.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+	})
+	t.Run("stop is not a failure", func(t *testing.T) {
+		p, _ := newTestProc(&Process{Command: "sleep 60", Restart: RestartNo})
+
+		done := make(chan error)
+		go func() { done <- p.Run(t.Context()) }()
+		<-p.started
+
+		err := p.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+	})
+	t.Run("process starts after its dependencies", func(t *testing.T) {
+		dep, _ := newTestProc(&Process{Command: "sleep 60", Restart: RestartNo})
+		p, out := newTestProc(&Process{Command: "sleep 60", Restart: RestartNo}, dep)
+		defer let.Halt(dep)
+		defer let.Halt(p)
+
+		done := make(chan error)
+		go func() { done <- p.Run(t.Context()) }()
+
+		select {
+		case <-p.started:
+			t.Fatal("process started before its dependency")
+		case <-time.After(10 * time.Millisecond):
+		}
+		require.Empty(t, out.String())
+
+		go dep.Run(t.Context())
+		<-p.started
+
+		err := p.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+	})
+}
+
+func TestHandleSignals(t *testing.T) {
+	// The process ignores SIGTERM so only the second signal ends it.
+	out := &syncBuffer{}
+	p := newProc(&Process{Command: `trap '' TERM; echo ready; sleep 60`, Restart: RestartNo}, out)
+
+	r := let.NewGroup()
+	r.Go(p)
+
+	sigs := make(chan os.Signal, 2)
+	go handleSignals(sigs, r)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Run(t.Context())
+	}()
+
+	// Ensure the trap is set.
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "ready\n")
+	}, 5*time.Second, time.Millisecond)
+
+	sigs <- syscall.SIGTERM
+
+	select {
+	case <-done:
+		t.Fatal("stopped on the first signal")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	sigs <- syscall.SIGTERM
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not closed on the second signal")
+	}
+}
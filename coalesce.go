@@ -0,0 +1,114 @@
+package let
+
+import (
+	"context"
+	"sync"
+)
+
+type flight struct {
+	done    chan struct{}
+	err     error
+	started bool
+}
+
+type coalesced struct {
+	Task
+
+	follow bool
+
+	m    sync.Mutex
+	curr *flight
+	next *flight
+}
+
+// Coalesce creates a Task where callers that arrive while a Run is in flight
+// share the result of that Run instead of running the given Task again.
+// The in-flight Run uses the context of the caller that started it.
+func Coalesce(t Task) Task {
+	return &coalesced{Task: t}
+}
+
+// CoalesceWithFollowUp is like [Coalesce] but callers that arrive while a Run is in flight
+// share the result of a single follow-up Run that starts after the current one finishes.
+// This is useful for refreshing a cache where the caller needs a result
+// that reflects the state at the time of the call.
+func CoalesceWithFollowUp(t Task) Task {
+	return &coalesced{Task: t, follow: true}
+}
+
+func (t *coalesced) lead(ctx context.Context, f *flight) error {
+	// Followers get ErrPanicked if the Run panics.
+	f.err = ErrPanicked
+	defer t.land(f)
+
+	f.err = t.Task.Run(ctx)
+	return f.err
+}
+
+func (t *coalesced) land(f *flight) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	close(f.done)
+	if t.curr == f {
+		// Promote the follow-up so no caller starts a flight
+		// before the followers take it.
+		t.curr = t.next
+		t.next = nil
+	}
+}
+
+func (t *coalesced) Run(ctx context.Context) error {
+	t.m.Lock()
+	if t.curr == nil {
+		f := &flight{done: make(chan struct{}), started: true}
+		t.curr = f
+		t.m.Unlock()
+		return t.lead(ctx, f)
+	}
+
+	curr := t.curr
+	if !curr.started {
+		// The follow-up is not started by its followers yet.
+		curr.started = true
+		t.m.Unlock()
+		return t.lead(ctx, curr)
+	}
+	if !t.follow {
+		t.m.Unlock()
+		return wait(ctx, curr)
+	}
+
+	if t.next == nil {
+		t.next = &flight{done: make(chan struct{})}
+	}
+	f := t.next
+	t.m.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-curr.done:
+	}
+
+	t.m.Lock()
+	if f.started {
+		t.m.Unlock()
+		return wait(ctx, f)
+	}
+
+	// This caller is the first one to notice the end of the previous Run.
+	// The follow-up is promoted to the current flight by the previous Run.
+	f.started = true
+	t.m.Unlock()
+
+	return t.lead(ctx, f)
+}
+
+func wait(ctx context.Context, f *flight) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-f.done:
+		return f.err
+	}
+}
@@ -0,0 +1,125 @@
+package let_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalesce(t *testing.T) {
+	run := func(t *testing.T, task let.Task, started chan struct{}, release chan struct{}) {
+		const N = 10
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task.Run(t.Context())
+		}()
+
+		// Ensure the run started.
+		<-started
+
+		for range N {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := task.Run(t.Context())
+				require.NoError(t, err)
+			}()
+		}
+
+		// Give the callers a chance to join the run in flight.
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+	}
+
+	t.Run("concurrent runs share the result", func(t *testing.T) {
+		var i atomic.Int32
+		started := make(chan struct{}, 1)
+		release := make(chan struct{})
+		task := let.Coalesce(let.New(func(ctx context.Context) error {
+			i.Add(1)
+			started <- struct{}{}
+			<-release
+			return nil
+		}))
+		defer let.Halt(task)
+
+		run(t, task, started, release)
+		require.Equal(t, int32(1), i.Load())
+	})
+	t.Run("follow-up run is shared", func(t *testing.T) {
+		var i atomic.Int32
+		started := make(chan struct{}, 2)
+		release := make(chan struct{})
+		task := let.CoalesceWithFollowUp(let.New(func(ctx context.Context) error {
+			i.Add(1)
+			started <- struct{}{}
+			<-release
+			return nil
+		}))
+		defer let.Halt(task)
+
+		run(t, task, started, release)
+		require.Equal(t, int32(2), i.Load())
+	})
+	t.Run("caller after the run takes the pending follow-up", func(t *testing.T) {
+		var i atomic.Int32
+		started := make(chan struct{})
+		release := make(chan struct{})
+		task := let.CoalesceWithFollowUp(let.New(func(ctx context.Context) error {
+			n := i.Add(1)
+			if n == 1 {
+				close(started)
+				<-release
+			}
+			return fmt.Errorf("run %d", n)
+		}))
+		defer let.Halt(task)
+
+		leader := make(chan error)
+		go func() {
+			leader <- task.Run(t.Context())
+		}()
+		<-started
+
+		// The follower requests the follow-up but does not wait for it.
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		err := task.Run(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		require.EqualError(t, <-leader, "run 1")
+
+		err = task.Run(t.Context())
+		require.EqualError(t, err, "run 2")
+		require.Equal(t, int32(2), i.Load())
+	})
+	t.Run("panic does not block later callers", func(t *testing.T) {
+		var i atomic.Int32
+		task := let.Coalesce(let.New(func(ctx context.Context) error {
+			if i.Add(1) == 1 {
+				panic("foo")
+			}
+			return nil
+		}))
+		defer let.Halt(task)
+
+		require.PanicsWithValue(t, "foo", func() {
+			task.Run(t.Context())
+		})
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, int32(2), i.Load())
+	})
+}
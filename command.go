@@ -0,0 +1,196 @@
+package let
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+type command struct {
+	Task
+
+	cmd *exec.Cmd
+
+	sig    os.Signal
+	logger *slog.Logger
+
+	m        sync.Mutex
+	started  bool
+	stopping bool
+	forced   bool
+	// exited is closed with the lock held after the process is reaped
+	// so the process group is not signaled once its ID may be reused.
+	exited chan struct{}
+}
+
+type CommandOption func(t *command)
+
+// WithStopSignal sets the signal sent to the process on Stop.
+// Defaults to SIGTERM.
+func WithStopSignal(sig os.Signal) CommandOption {
+	return func(t *command) {
+		t.sig = sig
+	}
+}
+
+// WithOutputLogger streams each line of stdout and stderr of the process to the given logger.
+// It overrides `Stdout` and `Stderr` of the command.
+func WithOutputLogger(l *slog.Logger) CommandOption {
+	return func(t *command) {
+		t.logger = l
+	}
+}
+
+// Command creates a Task that starts the given command and waits for it to exit.
+// Stop sends the stop signal to the process group of the process and waits for it to exit.
+// Close kills the process group of the process.
+// The command can be run only once; subsequent Runs return [ErrClosed].
+func Command(cmd *exec.Cmd, opts ...CommandOption) Task {
+	t := &command{
+		cmd: cmd,
+		sig: stopSignal,
+
+		exited: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.logger != nil {
+		cmd.Stdout = &lineLogger{l: t.logger, name: "stdout"}
+		cmd.Stderr = &lineLogger{l: t.logger, name: "stderr"}
+	}
+	setProcessGroup(cmd)
+
+	t.Task = Once(New(t.run))
+	return t
+}
+
+func (t *command) start() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.stopping {
+		if t.forced {
+			return ErrForceClosed
+		}
+		return ErrStopped
+	}
+	if err := t.cmd.Start(); err != nil {
+		return err
+	}
+
+	t.started = true
+	return nil
+}
+
+func (t *command) exit() {
+	t.m.Lock()
+	defer t.m.Unlock()
+	close(t.exited)
+}
+
+// signal calls `f` with the process if it is running
+// and reports whether it was running.
+// The command is not started afterwards.
+func (t *command) signal(forced bool, f func(p *os.Process)) bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.stopping = true
+	t.forced = t.forced || forced
+	if !t.started {
+		return false
+	}
+
+	select {
+	case <-t.exited:
+		return false
+	default:
+	}
+
+	f(t.cmd.Process)
+	return true
+}
+
+func (t *command) run(ctx context.Context) error {
+	if err := t.start(); err != nil {
+		t.exit()
+		return err
+	}
+
+	// Context is canceled by Close or by the caller.
+	stop := context.AfterFunc(ctx, func() {
+		t.signal(true, killProcessGroup)
+	})
+	defer stop()
+
+	err := t.cmd.Wait()
+	t.exit()
+
+	if l, ok := t.cmd.Stdout.(*lineLogger); ok {
+		l.flush()
+	}
+	if l, ok := t.cmd.Stderr.(*lineLogger); ok {
+		l.flush()
+	}
+
+	return err
+}
+
+func (t *command) Stop(ctx context.Context) error {
+	running := t.signal(false, func(p *os.Process) {
+		signalProcessGroup(p, t.sig)
+	})
+	if running {
+		select {
+		case <-ctx.Done():
+			return stopErr(ctx)
+		case <-t.exited:
+		}
+	}
+
+	return t.Task.Stop(ctx)
+}
+
+func (t *command) Close() error {
+	t.signal(true, killProcessGroup)
+	return t.Task.Close()
+}
+
+type lineLogger struct {
+	l    *slog.Logger
+	name string
+
+	m   sync.Mutex
+	buf []byte
+}
+
+func (w *lineLogger) Write(p []byte) (int, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.l.Info(string(w.buf[:i]), slog.String("stream", w.name))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+func (w *lineLogger) flush() {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if len(w.buf) == 0 {
+		return
+	}
+
+	w.l.Info(string(w.buf), slog.String("stream", w.name))
+	w.buf = nil
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package let
+
+import (
+	"os"
+	"os/exec"
+)
+
+// There is no SIGTERM on this platform.
+var stopSignal = os.Kill
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func signalProcessGroup(p *os.Process, sig os.Signal) {
+	p.Signal(sig)
+}
+
+func killProcessGroup(p *os.Process) {
+	p.Kill()
+}
@@ -0,0 +1,134 @@
+//go:build unix
+
+package let_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	t.Run("run waits for the process", func(t *testing.T) {
+		task := let.Command(exec.Command("true"))
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+
+		err = task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrClosed)
+	})
+	t.Run("run returns exit error", func(t *testing.T) {
+		task := let.Command(exec.Command("false"))
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		var exit_err *exec.ExitError
+		require.ErrorAs(t, err, &exit_err)
+	})
+	t.Run("stop sends the stop signal", func(t *testing.T) {
+		cmd := exec.Command("sh", "-c", `trap 'exit 42' USR1; echo ready; while :; do sleep 0.01; done`)
+		stdout, err := cmd.StdoutPipe()
+		require.NoError(t, err)
+
+		task := let.Command(cmd, let.WithStopSignal(syscall.SIGUSR1))
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		// Ensure the trap is set.
+		_, err = stdout.Read(make([]byte, 6))
+		require.NoError(t, err)
+
+		err = task.Stop(t.Context())
+		require.NoError(t, err)
+
+		err = <-done
+		var exit_err *exec.ExitError
+		require.ErrorAs(t, err, &exit_err)
+		require.Equal(t, 42, exit_err.ExitCode())
+	})
+	t.Run("close kills the process", func(t *testing.T) {
+		task := let.Command(exec.Command("sleep", "60"))
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		// Give the process a chance to start.
+		time.Sleep(50 * time.Millisecond)
+
+		err := task.Close()
+		require.NoError(t, err)
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("process is not killed")
+		}
+	})
+	t.Run("process group is not signaled after exit", func(t *testing.T) {
+		if _, err := os.Stat("/proc/self/stat"); err != nil {
+			t.Skip("process state is not available")
+		}
+
+		// The background process remains in the process group after the shell exits.
+		cmd := exec.Command("sh", "-c", `sleep 60 >/dev/null 2>&1 & echo $!`)
+		var out bytes.Buffer
+		cmd.Stdout = &out
+
+		task := let.Command(cmd)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+
+		pid, err := strconv.Atoi(strings.TrimSpace(out.String()))
+		require.NoError(t, err)
+		defer syscall.Kill(pid, syscall.SIGKILL)
+
+		err = task.Stop(t.Context())
+		require.NoError(t, err)
+		err = let.Halt(task)
+		require.NoError(t, err)
+
+		// Give the signal a chance to be delivered.
+		time.Sleep(50 * time.Millisecond)
+
+		// A killed process may remain as a zombie if nobody reaps it.
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		require.NoError(t, err, "the process group must not be signaled")
+		_, state, _ := strings.Cut(string(stat), ") ")
+		require.NotEqual(t, "Z", state[:1], "the process group must not be signaled")
+	})
+	t.Run("output is streamed to the logger", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(slog.NewTextHandler(&b, nil))
+
+		task := let.Command(exec.Command("sh", "-c", "echo foo; echo bar >&2; printf baz"), let.WithOutputLogger(l))
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+
+		out := b.String()
+		require.Contains(t, out, "msg=foo stream=stdout")
+		require.Contains(t, out, "msg=bar stream=stderr")
+		require.Contains(t, out, "msg=baz stream=stdout")
+	})
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package let
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const stopSignal = syscall.SIGTERM
+
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func signalProcessGroup(p *os.Process, sig os.Signal) {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		p.Signal(sig)
+		return
+	}
+
+	// Negative pid signals the process group.
+	syscall.Kill(-p.Pid, s)
+}
+
+func killProcessGroup(p *os.Process) {
+	signalProcessGroup(p, syscall.SIGKILL)
+}
@@ -0,0 +1,167 @@
+package let
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+)
+
+type connServe struct {
+	base Task
+
+	l net.Listener
+	h func(ctx context.Context, conn net.Conn) error
+
+	m       sync.Mutex
+	conns   map[net.Conn]struct{}
+	closed  bool
+	cancel  context.CancelFunc
+	drained chan struct{}
+}
+
+// ServeConn creates a Task that accepts connections from the given listener
+// and handles each of them in a new goroutine.
+// The connection is closed when the handler returns and the error from the handler is discarded.
+// Stop stops accepting new connections and waits for the in-flight handlers to return.
+// Close closes all the connections and cancels the context passed to the handlers.
+func ServeConn(l net.Listener, handler func(ctx context.Context, conn net.Conn) error) Task {
+	t := &connServe{
+		l: l,
+		h: handler,
+
+		conns:   map[net.Conn]struct{}{},
+		cancel:  func() {},
+		drained: make(chan struct{}),
+	}
+	t.base = Once(New(t.serve))
+
+	return t
+}
+
+func (t *connServe) serve(ctx context.Context) error {
+	defer t.shutdown()
+
+	// Handlers outlive the Run on Stop so they are canceled only by Close.
+	// The context is released once all the handlers returned after the shutdown.
+	ctx_conn, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	if !t.setCancel(cancel) {
+		cancel()
+		return nil
+	}
+
+	stop := context.AfterFunc(ctx, t.shutdown)
+	defer stop()
+
+	for {
+		conn, err := t.l.Accept()
+		if err != nil {
+			if t.isClosed() {
+				return nil
+			}
+			return err
+		}
+
+		t.track(ctx_conn, conn)
+	}
+}
+
+func (t *connServe) setCancel(cancel context.CancelFunc) bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.closed {
+		return false
+	}
+
+	t.cancel = cancel
+	return true
+}
+
+func (t *connServe) isClosed() bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return t.closed
+}
+
+func (t *connServe) track(ctx context.Context, conn net.Conn) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.closed {
+		conn.Close()
+		return
+	}
+
+	t.conns[conn] = struct{}{}
+	goTask(ctx, t, func(ctx context.Context) {
+		defer t.untrack(conn)
+		defer conn.Close()
+
+		t.h(ctx, conn)
+	})
+}
+
+func (t *connServe) untrack(conn net.Conn) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	delete(t.conns, conn)
+	if t.closed && len(t.conns) == 0 {
+		t.cancel()
+		close(t.drained)
+	}
+}
+
+// shutdown stops accepting new connections.
+func (t *connServe) shutdown() {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.closed {
+		return
+	}
+
+	t.closed = true
+	t.l.Close()
+	if len(t.conns) == 0 {
+		t.cancel()
+		close(t.drained)
+	}
+}
+
+func (t *connServe) Run(ctx context.Context) error {
+	return t.base.Run(ctx)
+}
+
+func (t *connServe) Stop(ctx context.Context) error {
+	t.shutdown()
+
+	select {
+	case <-ctx.Done():
+		return stopErr(ctx)
+	case <-t.drained:
+	}
+
+	return t.base.Stop(ctx)
+}
+
+func (t *connServe) Close() error {
+	t.shutdown()
+
+	t.m.Lock()
+	t.cancel()
+	errs := []error{}
+	for conn := range t.conns {
+		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	t.m.Unlock()
+
+	t.base.Close()
+	return errors.Join(errs...)
+}
+
+func (t *connServe) Wait() error {
+	err := t.base.Wait()
+	<-t.drained
+	return err
+}
@@ -0,0 +1,183 @@
+package let_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeConn(t *testing.T) {
+	listen := func(t *testing.T) net.Listener {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		return l
+	}
+	echo := func(ctx context.Context, conn net.Conn) error {
+		_, err := io.Copy(conn, conn)
+		return err
+	}
+
+	t.Run("connections are handled", func(t *testing.T) {
+		l := listen(t)
+		task := let.ServeConn(l, echo)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		conn, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("foo"))
+		require.NoError(t, err)
+
+		b := make([]byte, 3)
+		_, err = io.ReadFull(conn, b)
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(b))
+
+		conn.Close()
+		err = task.Stop(t.Context())
+		require.NoError(t, err)
+
+		err = <-done
+		require.NoError(t, err)
+	})
+	t.Run("stop waits for in-flight connections", func(t *testing.T) {
+		l := listen(t)
+		c := make(chan struct{})
+		task := let.ServeConn(l, func(ctx context.Context, conn net.Conn) error {
+			c <- struct{}{}
+			<-c
+			return nil
+		})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		conn, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// Ensure the handler started.
+		<-c
+
+		stopped := make(chan error)
+		go func() {
+			stopped <- task.Stop(t.Context())
+		}()
+
+		// Run returns as the listener is closed.
+		err = <-done
+		require.NoError(t, err)
+
+		// New connections are refused.
+		_, err = net.Dial("tcp", l.Addr().String())
+		require.Error(t, err)
+
+		select {
+		case <-stopped:
+			t.Fatal("stop returned before the handler returns")
+		default:
+		}
+
+		c <- struct{}{}
+		err = <-stopped
+		require.NoError(t, err)
+	})
+	t.Run("stop does not cancel in-flight handlers", func(t *testing.T) {
+		l := listen(t)
+		c := make(chan struct{})
+		result := make(chan error, 1)
+		task := let.ServeConn(l, func(ctx context.Context, conn net.Conn) error {
+			read := make(chan struct{})
+			go func() {
+				defer close(read)
+				io.Copy(io.Discard, conn)
+			}()
+
+			close(c)
+			select {
+			case <-ctx.Done():
+				result <- ctx.Err()
+			case <-read:
+				result <- nil
+			}
+			return nil
+		})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		conn, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// Ensure the handler started.
+		<-c
+
+		stopped := make(chan error)
+		go func() {
+			stopped <- task.Stop(t.Context())
+		}()
+
+		// Run returns as the listener is closed.
+		err = <-done
+		require.NoError(t, err)
+
+		select {
+		case err := <-result:
+			t.Fatalf("handler returned before the connection is closed: %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		conn.Close()
+		require.NoError(t, <-result)
+		require.NoError(t, <-stopped)
+	})
+	t.Run("close closes connections", func(t *testing.T) {
+		l := listen(t)
+		c := make(chan struct{})
+		task := let.ServeConn(l, func(ctx context.Context, conn net.Conn) error {
+			close(c)
+			_, err := io.Copy(io.Discard, conn)
+			return err
+		})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		conn, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// Ensure the handler started.
+		<-c
+
+		err = task.Close()
+		require.NoError(t, err)
+
+		<-done
+		task.Wait()
+
+		_, err = conn.Read(make([]byte, 1))
+		require.ErrorIs(t, err, io.EOF)
+	})
+}
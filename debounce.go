@@ -0,0 +1,123 @@
+package let
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type triggered struct {
+	root Task
+	task Task
+}
+
+func newTriggered(t Task, f func(ctx context.Context, trig <-chan struct{}) error) (Task, func()) {
+	trig := make(chan struct{}, 1)
+	trigger := func() {
+		select {
+		case trig <- struct{}{}:
+		default:
+			// Already triggered.
+		}
+	}
+
+	root := New(func(ctx context.Context) error {
+		err := f(ctx, trig)
+		if errors.Is(err, ErrClosed) {
+			return nil
+		}
+		return err
+	})
+
+	return &triggered{root, t}, trigger
+}
+
+// Debounce creates a Task that runs the given Task once the returned trigger
+// has not been called for `d`.
+// Bursts of triggers are collapsed into a single run.
+// Run blocks until the Task is stopped or the given Task returns an error.
+func Debounce(d time.Duration, t Task) (Task, func()) {
+	return DebounceWithClock(RealClock, d, t)
+}
+
+// DebounceWithClock is like [Debounce] but the time is measured by the given Clock.
+func DebounceWithClock(c Clock, d time.Duration, t Task) (Task, func()) {
+	return newTriggered(t, func(ctx context.Context, trig <-chan struct{}) error {
+		timer := c.NewTimer(d)
+		timer.Stop()
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-trig:
+				timer.Reset(d)
+			case <-timer.C():
+				if err := t.Run(ctx); err != nil {
+					return err
+				}
+			}
+		}
+	})
+}
+
+// Throttle creates a Task that runs the given Task when the returned trigger is called,
+// at most once per `d`.
+// Triggers during the interval are collapsed into a single run at the end of the interval.
+// Run blocks until the Task is stopped or the given Task returns an error.
+func Throttle(d time.Duration, t Task) (Task, func()) {
+	return ThrottleWithClock(RealClock, d, t)
+}
+
+// ThrottleWithClock is like [Throttle] but the time is measured by the given Clock.
+func ThrottleWithClock(c Clock, d time.Duration, t Task) (Task, func()) {
+	return newTriggered(t, func(ctx context.Context, trig <-chan struct{}) error {
+		timer := c.NewTimer(d)
+		timer.Stop()
+		defer timer.Stop()
+
+		last := time.Time{}
+		pending := false
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-trig:
+				if pending {
+					continue
+				}
+				pending = true
+				timer.Reset(max(d-c.Now().Sub(last), 0))
+			case <-timer.C():
+				pending = false
+				last = c.Now()
+				if err := t.Run(ctx); err != nil {
+					return err
+				}
+			}
+		}
+	})
+}
+
+func (t *triggered) Run(ctx context.Context) error {
+	return t.root.Run(ctx)
+}
+
+func (t *triggered) Stop(ctx context.Context) error {
+	// Stop the given Task first so the current run finishes gracefully.
+	err := t.task.Stop(ctx)
+	t.root.Stop(ctx)
+	return err
+}
+
+func (t *triggered) Close() error {
+	err := t.task.Close()
+	t.root.Close()
+	return err
+}
+
+func (t *triggered) Wait() error {
+	t.task.Wait()
+	return t.root.Wait()
+}
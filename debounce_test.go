@@ -0,0 +1,147 @@
+package let_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebounce(t *testing.T) {
+	t.Run("runs after the quiet period", func(t *testing.T) {
+		clock := let.NewFakeClock(time.Now())
+
+		c := make(chan struct{}, 10)
+		task, trigger := let.DebounceWithClock(clock, time.Second, let.New(func(ctx context.Context) error {
+			c <- struct{}{}
+			return nil
+		}))
+		defer let.Halt(task)
+
+		go task.Run(t.Context())
+
+		trigger()
+		err := clock.BlockUntil(t.Context(), 1)
+		require.NoError(t, err)
+
+		clock.Advance(time.Second - time.Nanosecond)
+		require.Empty(t, c)
+
+		clock.Advance(time.Nanosecond)
+		<-c
+	})
+	t.Run("triggers are collapsed", func(t *testing.T) {
+		clock := let.NewFakeClock(time.Now())
+
+		i := atomic.Int32{}
+		started := make(chan struct{})
+		release := make(chan struct{})
+		c := make(chan struct{}, 10)
+		task, trigger := let.DebounceWithClock(clock, time.Second, let.New(func(ctx context.Context) error {
+			if i.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			c <- struct{}{}
+			return nil
+		}))
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		trigger()
+		err := clock.BlockUntil(t.Context(), 1)
+		require.NoError(t, err)
+		clock.Advance(time.Second)
+		<-started
+
+		// Triggers during the run are collapsed into one.
+		for range 5 {
+			trigger()
+		}
+		close(release)
+		<-c
+
+		err = clock.BlockUntil(t.Context(), 1)
+		require.NoError(t, err)
+		clock.Advance(time.Second)
+		<-c
+
+		err = task.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+		require.Equal(t, int32(2), i.Load())
+	})
+	t.Run("stop ends the run", func(t *testing.T) {
+		clock := let.NewFakeClock(time.Now())
+
+		task, trigger := let.DebounceWithClock(clock, time.Hour, let.Nop())
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		// Ensure the run started.
+		trigger()
+		err := clock.BlockUntil(t.Context(), 1)
+		require.NoError(t, err)
+
+		err = task.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+	})
+}
+
+func TestThrottle(t *testing.T) {
+	t.Run("runs at most once per interval", func(t *testing.T) {
+		clock := let.NewFakeClock(time.Now())
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		c := make(chan time.Time, 10)
+		task, trigger := let.ThrottleWithClock(clock, time.Second, let.New(func(ctx context.Context) error {
+			c <- clock.Now()
+			select {
+			case <-started:
+			default:
+				close(started)
+				<-release
+			}
+			return nil
+		}))
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		// First trigger runs immediately.
+		trigger()
+		t0 := <-c
+		<-started
+
+		// Following triggers are collapsed into a single run after the interval.
+		for range 5 {
+			trigger()
+		}
+		close(release)
+
+		err := clock.BlockUntil(t.Context(), 1)
+		require.NoError(t, err)
+
+		clock.Advance(time.Second - time.Nanosecond)
+		require.Empty(t, c)
+
+		clock.Advance(time.Nanosecond)
+		t1 := <-c
+		require.Equal(t, time.Second, t1.Sub(t0))
+
+		err = task.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+		require.Empty(t, c)
+	})
+}
@@ -1,7 +1,71 @@
 package let
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrClosed = errors.New("closed")
+	ErrClosed      = errors.New("closed")
+	ErrBreakerOpen = errors.New("breaker open")
+	ErrBusy        = errors.New("busy")
+
+	// ErrStopped is returned by Run of a Task that was requested to stop gracefully.
+	// It matches [ErrClosed].
+	ErrStopped = fmt.Errorf("stopped: %w", ErrClosed)
+	// ErrForceClosed is returned by Run of a Task that was closed forcibly.
+	// It matches [ErrClosed].
+	ErrForceClosed = fmt.Errorf("closed forcibly: %w", ErrClosed)
+	// ErrTimeout is returned by Stop if the Task did not stop before the deadline.
+	// The returned error also matches [context.DeadlineExceeded].
+	ErrTimeout = errors.New("timed out")
+	// ErrPanicked is matched by [PanicError].
+	ErrPanicked = errors.New("panicked")
+	// ErrDependencyFailed is matched by [DependencyError].
+	// It does not match [ErrClosed] since the failure must not be ignored.
+	ErrDependencyFailed = errors.New("dependency failed")
 )
+
+// PanicError is returned when a Task body panics.
+// See [Recover].
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panicked: %v", e.Value)
+}
+
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrPanicked, err}
+	}
+	return []error{ErrPanicked}
+}
+
+// DependencyError is returned when a Task is not run because a Task it depends on failed.
+type DependencyError struct {
+	// Err is the error of the failed dependency.
+	Err error
+}
+
+func (e *DependencyError) Error() string {
+	return fmt.Sprintf("dependency failed: %v", e.Err)
+}
+
+func (e *DependencyError) Unwrap() []error {
+	return []error{ErrDependencyFailed, e.Err}
+}
+
+// stopErr returns the error of Stop whose context is done.
+func stopErr(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package let
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+)
+
+// ForEach creates a Task that calls `fn` for each value of `seq`
+// with at most `concurrency` calls in flight.
+// On the first error, it stops dispatching, cancels the context passed to the calls in flight,
+// and returns the error once they are done.
+// Stop stops dispatching and waits for the calls in flight,
+// and Close cancels the context passed to `fn`.
+// If the context of Run is done or the Task is closed, Run returns the error of the context.
+func ForEach[T any](seq iter.Seq[T], concurrency int, fn func(ctx context.Context, v T) error) Task {
+	return newForEach(seq, concurrency, fn, true)
+}
+
+// ForEachAll is like [ForEach] but does not stop on errors.
+// It calls `fn` for every value and returns all the errors joined by [errors.Join].
+func ForEachAll[T any](seq iter.Seq[T], concurrency int, fn func(ctx context.Context, v T) error) Task {
+	return newForEach(seq, concurrency, fn, false)
+}
+
+func newForEach[T any](seq iter.Seq[T], concurrency int, fn func(ctx context.Context, v T) error, fail_fast bool) Task {
+	if concurrency < 1 {
+		panic("concurrency must be larger than 0")
+	}
+
+	return NewGraceful(0, func(ctx context.Context) error {
+		stopping := Stopping(ctx)
+
+		parent := ctx
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		var (
+			wg   sync.WaitGroup
+			m    sync.Mutex
+			errs []error
+		)
+
+		sem := make(chan struct{}, concurrency)
+
+	L:
+		for v := range seq {
+			select {
+			case <-stopping:
+				break L
+			case <-ctx.Done():
+				break L
+			default:
+			}
+
+			select {
+			case <-stopping:
+				break L
+			case <-ctx.Done():
+				break L
+			case sem <- struct{}{}:
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				err := fn(ctx, v)
+				if err == nil {
+					return
+				}
+
+				m.Lock()
+				errs = append(errs, err)
+				m.Unlock()
+
+				if fail_fast {
+					cancel(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if fail_fast && len(errs) > 0 {
+			return errs[0]
+		}
+
+		// Values are not processed if the caller's context is done or the Task is closed.
+		return errors.Join(append(errs, parent.Err())...)
+	})
+}
@@ -0,0 +1,153 @@
+package let_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEach(t *testing.T) {
+	t.Run("calls for every value", func(t *testing.T) {
+		m := sync.Mutex{}
+		vs := []int{}
+		task := let.ForEach(slices.Values([]int{1, 2, 3, 4, 5}), 2, func(ctx context.Context, v int) error {
+			m.Lock()
+			defer m.Unlock()
+			vs = append(vs, v)
+			return nil
+		})
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+
+		slices.Sort(vs)
+		require.Equal(t, []int{1, 2, 3, 4, 5}, vs)
+	})
+	t.Run("calls in flight are bounded", func(t *testing.T) {
+		n := atomic.Int32{}
+		peak := atomic.Int32{}
+		task := let.ForEach(slices.Values(make([]int, 20)), 3, func(ctx context.Context, v int) error {
+			c := n.Add(1)
+			defer n.Add(-1)
+			for {
+				m := peak.Load()
+				if c <= m || peak.CompareAndSwap(m, c) {
+					break
+				}
+			}
+			return nil
+		})
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+		require.LessOrEqual(t, peak.Load(), int32(3))
+	})
+	t.Run("fail fast", func(t *testing.T) {
+		i := atomic.Int32{}
+		task := let.ForEach(slices.Values([]int{1, 2, 3, 4, 5}), 1, func(ctx context.Context, v int) error {
+			i.Add(1)
+			if v == 2 {
+				return io.EOF
+			}
+			return nil
+		})
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, io.EOF)
+		require.LessOrEqual(t, i.Load(), int32(3))
+	})
+	t.Run("collect all", func(t *testing.T) {
+		i := atomic.Int32{}
+		task := let.ForEachAll(slices.Values([]int{1, 2, 3, 4, 5}), 2, func(ctx context.Context, v int) error {
+			i.Add(1)
+			switch v {
+			case 2:
+				return io.EOF
+			case 4:
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		})
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, io.EOF)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		require.Equal(t, int32(5), i.Load())
+	})
+	t.Run("stop stops dispatching", func(t *testing.T) {
+		c := make(chan struct{})
+		i := atomic.Int32{}
+		task := let.ForEach(slices.Values([]int{1, 2, 3}), 1, func(ctx context.Context, v int) error {
+			i.Add(1)
+			close(c)
+			<-let.Stopping(ctx)
+			return ctx.Err()
+		})
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+		<-c
+
+		err := task.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+		require.Equal(t, int32(1), i.Load())
+	})
+	t.Run("close cancels calls in flight", func(t *testing.T) {
+		c := make(chan struct{})
+		task := let.ForEach(slices.Values([]int{1}), 1, func(ctx context.Context, v int) error {
+			close(c)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+		<-c
+
+		task.Close()
+		err := <-done
+		require.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("done context is reported", func(t *testing.T) {
+		for _, fail_fast := range []bool{true, false} {
+			t.Run(fmt.Sprintf("fail fast %v", fail_fast), func(t *testing.T) {
+				c := make(chan struct{})
+				fn := func(ctx context.Context, v int) error {
+					if v == 1 {
+						close(c)
+					}
+					<-ctx.Done()
+					return nil
+				}
+
+				var task let.Task
+				if fail_fast {
+					task = let.ForEach(slices.Values([]int{1, 2, 3}), 1, fn)
+				} else {
+					task = let.ForEachAll(slices.Values([]int{1, 2, 3}), 1, fn)
+				}
+				defer let.Halt(task)
+
+				ctx, cancel := context.WithCancel(t.Context())
+				done := make(chan error)
+				go func() { done <- task.Run(ctx) }()
+				<-c
+
+				cancel()
+				require.ErrorIs(t, <-done, context.Canceled)
+			})
+		}
+	})
+}
@@ -2,6 +2,8 @@ package let
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 )
 
@@ -27,25 +29,56 @@ func NewGroupWithContext(ctx context.Context) Runner {
 // NewRunner creates a Runner that runs multiple tasks simultaneously.
 // If a task fails, all its child tasks are stopped.
 // The [Stop], [Close], and [Wait] methods return the error from the first failed task.
+// After a task fails, Go returns a [DependencyError] with the error that also matches [ErrClosed].
 func NewGroup() Runner {
 	return NewGroupWithContext(context.Background())
 }
 
 func (r *group) run(t Task, ctx context.Context) {
 	r.wg.Add(1)
-	go func() {
+	goTask(ctx, t, func(ctx context.Context) {
 		defer r.wg.Done()
 
 		err := t.Run(ctx)
-		if r.stop() {
+		if !r.fail(err) {
 			// This is not the first return of the Run
 			// or the Run is stopped by context cancel.
 			return
 		}
 
-		r.err = err
 		r.stopTasks()
-	}()
+	})
+}
+
+// fail stops the group with the given error
+// and reports whether it is the first stop.
+func (r *group) fail(err error) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.stopped {
+		return false
+	}
+
+	r.stopped = true
+	r.queue = nil
+	r.err = err
+
+	return true
+}
+
+func (r *group) Go(t Task) error {
+	err := r.runner.Go(t)
+	if !errors.Is(err, ErrClosed) {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err != nil {
+		// Keep it matching ErrClosed as Runner.Go documents.
+		return fmt.Errorf("%w: %w", ErrClosed, &DependencyError{Err: r.err})
+	}
+	return err
 }
 
 func (r *group) Wait() error {
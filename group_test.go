@@ -30,4 +30,24 @@ func TestGroup(t *testing.T) {
 		err := r.Wait()
 		require.Equal(t, io.EOF, err)
 	})
+	t.Run("go after failure returns dependency error", func(t *testing.T) {
+		r := let.NewGroup()
+		go r.Run(t.Context())
+
+		r.Go(let.New(func(ctx context.Context) error {
+			return io.EOF
+		}))
+
+		err := r.Wait()
+		require.Equal(t, io.EOF, err)
+
+		err = r.Go(let.Nop())
+		require.ErrorIs(t, err, let.ErrDependencyFailed)
+		require.ErrorIs(t, err, let.ErrClosed)
+		require.ErrorIs(t, err, io.EOF)
+
+		var dep_err *let.DependencyError
+		require.ErrorAs(t, err, &dep_err)
+		require.Equal(t, io.EOF, dep_err.Err)
+	})
 }
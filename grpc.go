@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"sync"
+	"time"
 )
 
 type GrpcServer interface {
@@ -12,47 +13,88 @@ type GrpcServer interface {
 	Stop()
 }
 
-type grpcServe struct {
-	s GrpcServer
+// GrpcHealthServer is a subset of [google.golang.org/grpc/health.Server].
+type GrpcHealthServer interface {
+	// Shutdown sets all serving status to NOT_SERVING
+	// and ignores all future status changes.
+	Shutdown()
+}
 
-	Task
-	done chan struct{}
-	stop func()
+type GrpcServeConfig struct {
+	// Health is shut down when Stop or Close begins
+	// so load balancers stop sending new requests while the in-flight ones are drained.
+	Health GrpcHealthServer
+	// HealthDelay is the duration between the shutdown of the health server and
+	// the graceful stop of the server on Stop, so load balancers can observe
+	// NOT_SERVING before new connections are refused.
+	// The delay ends early if the context given to Stop is done or on Close.
+	HealthDelay time.Duration
+	// Clock measures the delay.
+	// Defaults to [RealClock].
+	Clock Clock
 }
 
+// GrpcServe creates a Task that runs [GrpcServer.Serve] with the given listener.
+// Stop gracefully stops the server until the context given to Stop is done,
+// and then stops the server forcefully.
 func GrpcServe(s GrpcServer, l net.Listener) Task {
-	base := Once(New(func(ctx context.Context) error {
-		return s.Serve(l)
-	}))
-	done := make(chan struct{})
-	stop := sync.OnceFunc(func() {
-		s.GracefulStop()
-		base.Stop(context.Background())
-		close(done)
-	})
-
-	t := &grpcServe{
-		s: s,
-
-		Task: base,
-		done: done,
-		stop: stop,
+	return GrpcServeWithConfig(s, l, GrpcServeConfig{})
+}
+
+// GrpcServeWithConfig is like [GrpcServe] but configured by the given [GrpcServeConfig].
+func GrpcServeWithConfig(s GrpcServer, l net.Listener, cfg GrpcServeConfig) Task {
+	c := clockOr(cfg.Clock)
+	shutdownHealth := func() {
+		if cfg.Health != nil {
+			cfg.Health.Shutdown()
+		}
 	}
 
-	return t
-}
+	forced := make(chan struct{})
+	force := sync.OnceFunc(func() { close(forced) })
 
-func (t *grpcServe) Stop(ctx context.Context) error {
-	t.stop()
-	return nil
-}
+	var t Task
+	t = Server(
+		func(ctx context.Context) error {
+			return s.Serve(l)
+		},
+		func(ctx context.Context) error {
+			shutdownHealth()
 
-func (t *grpcServe) Close() error {
-	t.s.Stop()
-	return nil
-}
+			if cfg.HealthDelay > 0 {
+				timer := c.NewTimer(cfg.HealthDelay)
+				defer timer.Stop()
+
+				select {
+				case <-timer.C():
+				case <-forced:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			stopped := make(chan struct{})
+			goTask(ctx, t, func(context.Context) {
+				defer close(stopped)
+				s.GracefulStop()
+			})
 
-func (t *grpcServe) Wait() error {
-	<-t.done
-	return t.Task.Wait()
+			select {
+			case <-stopped:
+				return nil
+			case <-ctx.Done():
+				// Server stops the server forcefully, which makes the GracefulStop return.
+				return ctx.Err()
+			}
+		},
+		func() error {
+			force()
+			shutdownHealth()
+			s.Stop()
+			return nil
+		},
+	)
+
+	return t
 }
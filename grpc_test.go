@@ -0,0 +1,188 @@
+package let_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGrpcServer is a [let.GrpcServer] backed by fakeServable.
+type fakeGrpcServer struct {
+	*fakeServable
+}
+
+func newFakeGrpcServer() fakeGrpcServer {
+	return fakeGrpcServer{newFakeServable()}
+}
+
+func (s fakeGrpcServer) Serve(l net.Listener) error {
+	return s.fakeServable.Serve()
+}
+
+func (s fakeGrpcServer) GracefulStop() {
+	s.Shutdown(context.Background())
+}
+
+func (s fakeGrpcServer) Stop() {
+	s.Close()
+}
+
+// forced reports whether the server is stopped forcefully.
+func (s fakeGrpcServer) forced() bool {
+	return slices.Contains(s.Calls(), "close")
+}
+
+type fakeHealthServer struct {
+	shutdown atomic.Bool
+}
+
+func (h *fakeHealthServer) Shutdown() {
+	h.shutdown.Store(true)
+}
+
+func TestGrpcServe(t *testing.T) {
+	t.Run("stop waits for drain", func(t *testing.T) {
+		s := newFakeGrpcServer()
+		h := &fakeHealthServer{}
+		task := let.GrpcServeWithConfig(s, nil, let.GrpcServeConfig{Health: h})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		stopped := make(chan error)
+		go func() {
+			stopped <- task.Stop(t.Context())
+		}()
+
+		require.Eventually(t, h.shutdown.Load, time.Second, time.Millisecond)
+
+		select {
+		case <-stopped:
+			t.Fatal("stop returned before the drain")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		close(s.drained)
+		require.NoError(t, <-stopped)
+		require.False(t, s.forced())
+		require.ErrorIs(t, <-done, io.EOF)
+	})
+	t.Run("stop escalates on deadline", func(t *testing.T) {
+		s := newFakeGrpcServer()
+		task := let.GrpcServe(s, nil)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		err := task.Stop(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorIs(t, <-done, io.EOF)
+
+		task.Wait()
+		require.True(t, s.forced())
+	})
+	t.Run("close stops the server", func(t *testing.T) {
+		s := newFakeGrpcServer()
+		h := &fakeHealthServer{}
+		task := let.GrpcServeWithConfig(s, nil, let.GrpcServeConfig{Health: h})
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		// Ensure the server is serving.
+		<-s.serving
+
+		err := task.Close()
+		require.NoError(t, err)
+		require.ErrorIs(t, <-done, io.EOF)
+		require.True(t, h.shutdown.Load())
+
+		task.Wait()
+	})
+	t.Run("graceful stop is delayed after health shutdown", func(t *testing.T) {
+		clock := let.NewFakeClock(time.Now())
+
+		s := newFakeGrpcServer()
+		close(s.drained)
+
+		h := &fakeHealthServer{}
+		task := let.GrpcServeWithConfig(s, nil, let.GrpcServeConfig{
+			Health:      h,
+			HealthDelay: time.Second,
+			Clock:       clock,
+		})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		// Ensure the server is serving.
+		<-s.serving
+
+		stopped := make(chan error)
+		go func() {
+			stopped <- task.Stop(t.Context())
+		}()
+
+		err := clock.BlockUntil(t.Context(), 1)
+		require.NoError(t, err)
+		require.True(t, h.shutdown.Load())
+
+		select {
+		case <-s.stopped:
+			t.Fatal("server stopped before the delay")
+		default:
+		}
+
+		clock.Advance(time.Second)
+		require.NoError(t, <-stopped)
+		require.False(t, s.forced())
+		require.ErrorIs(t, <-done, io.EOF)
+	})
+	t.Run("delay is bounded by stop deadline", func(t *testing.T) {
+		s := newFakeGrpcServer()
+		task := let.GrpcServeWithConfig(s, nil, let.GrpcServeConfig{
+			Health:      &fakeHealthServer{},
+			HealthDelay: time.Hour,
+		})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		// Ensure the server is serving.
+		<-s.serving
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		err := task.Stop(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorIs(t, <-done, io.EOF)
+
+		task.Wait()
+		require.True(t, s.forced())
+	})
+}
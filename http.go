@@ -2,84 +2,142 @@ package let
 
 import (
 	"context"
+	"errors"
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 )
 
+// HttpServeTask is a Task that serves an [http.Server].
+type HttpServeTask interface {
+	Task
+
+	// InFlight returns the number of requests being handled.
+	InFlight() int
+	// Idle returns the number of idle connections.
+	Idle() int
+}
+
 type httpServe struct {
-	s *http.Server
+	Task
 
-	base Task
-	done chan struct{}
+	s *http.Server
 
-	stop     func()
-	stop_err error
+	in_flight atomic.Int64
 
-	close     func()
-	close_err error
+	m     sync.Mutex
+	conns map[net.Conn]http.ConnState
+	idle  int
 }
 
-func newHttpServe(s *http.Server, base Task) Task {
-	base = Once(base)
-	done := make(chan struct{})
-	close := sync.OnceFunc(func() {
-		close(done)
-	})
-
+func newHttpServe(s *http.Server, serve func(ctx context.Context) error) HttpServeTask {
 	t := &httpServe{
+		Task: Server(serve, s.Shutdown, s.Close),
+
 		s: s,
 
-		base: base,
-		done: done,
+		conns: map[net.Conn]http.ConnState{},
 	}
-	t.stop = sync.OnceFunc(func() {
-		ctx := context.Background()
-		t.stop_err = s.Shutdown(ctx)
-		base.Stop(ctx)
-		close()
-	})
-	t.close = sync.OnceFunc(func() {
-		t.close_err = s.Close()
-		base.Close()
-		close()
-	})
+	t.track()
 
 	return t
 }
 
-func HttpListenAndServe(s *http.Server) Task {
-	return newHttpServe(s, New(func(ctx context.Context) error {
-		return s.ListenAndServe()
-	}))
+// track hooks the handler and the connection state of the server
+// to count in-flight requests and idle connections.
+func (t *httpServe) track() {
+	h := t.s.Handler
+	if h == nil {
+		h = http.DefaultServeMux
+	}
+	t.s.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.in_flight.Add(1)
+		defer t.in_flight.Add(-1)
+		h.ServeHTTP(w, r)
+	})
+
+	f := t.s.ConnState
+	t.s.ConnState = func(conn net.Conn, state http.ConnState) {
+		t.setConnState(conn, state)
+		if f != nil {
+			f(conn, state)
+		}
+	}
 }
 
-func HttpServe(s *http.Server, l net.Listener) Task {
-	return newHttpServe(s, New(func(ctx context.Context) error {
-		return s.Serve(l)
-	}))
+func (t *httpServe) setConnState(conn net.Conn, state http.ConnState) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.conns[conn] == http.StateIdle {
+		t.idle--
+	}
+	switch state {
+	case http.StateHijacked, http.StateClosed:
+		delete(t.conns, conn)
+		return
+	case http.StateIdle:
+		t.idle++
+	}
+	t.conns[conn] = state
 }
 
-func (t *httpServe) Run(ctx context.Context) error {
-	return t.base.Run(ctx)
+// HttpListenAndServe creates a Task that runs [http.Server.ListenAndServe].
+// The handler and the ConnState hook of the given server are wrapped
+// to count in-flight requests and idle connections.
+func HttpListenAndServe(s *http.Server) HttpServeTask {
+	return newHttpServe(s, func(ctx context.Context) error {
+		return s.ListenAndServe()
+	})
 }
 
-func (t *httpServe) Stop(ctx context.Context) error {
-	go t.stop()
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-t.done:
-		return t.stop_err
+// HttpServe creates a Task that runs [http.Server.Serve] with the given listener.
+// The handler and the ConnState hook of the given server are wrapped
+// to count in-flight requests and idle connections.
+func HttpServe(s *http.Server, l net.Listener) HttpServeTask {
+	return newHttpServe(s, func(ctx context.Context) error {
+		return s.Serve(l)
+	})
+}
+
+// HttpServeMulti creates a Task that runs [http.Server.Serve] on each of the given listeners.
+// If serving on any of the listeners fails, the server is closed so serving on the others stops too.
+// Run returns the first error.
+func HttpServeMulti(s *http.Server, ls ...net.Listener) HttpServeTask {
+	if len(ls) == 0 {
+		panic("at least one listener is required")
 	}
+	var t HttpServeTask
+	t = newHttpServe(s, func(ctx context.Context) error {
+		errs := make(chan error, len(ls))
+		for _, l := range ls {
+			goTask(ctx, t, func(ctx context.Context) {
+				errs <- s.Serve(l)
+			})
+		}
+
+		err := <-errs
+		if !errors.Is(err, http.ErrServerClosed) {
+			// Serving on one of the listeners failed.
+			s.Close()
+		}
+		for range len(ls) - 1 {
+			<-errs
+		}
+
+		return err
+	})
+
+	return t
 }
 
-func (t *httpServe) Close() error {
-	t.close()
-	return t.close_err
+func (t *httpServe) InFlight() int {
+	return int(t.in_flight.Load())
 }
 
-func (t *httpServe) Wait() error {
-	<-t.done
-	return t.base.Wait()
+func (t *httpServe) Idle() int {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return t.idle
 }
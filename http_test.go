@@ -0,0 +1,140 @@
+package let_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpServe(t *testing.T) {
+	serve := func(t *testing.T, h http.HandlerFunc) (let.HttpServeTask, string, chan error) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		task := let.HttpServe(&http.Server{Handler: h}, l)
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		return task, "http://" + l.Addr().String(), done
+	}
+
+	t.Run("in-flight requests are counted", func(t *testing.T) {
+		c := make(chan struct{})
+		task, url, done := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			c <- struct{}{}
+			<-c
+		})
+		defer let.Halt(task)
+
+		res := make(chan error)
+		go func() {
+			_, err := http.Get(url)
+			res <- err
+		}()
+
+		// Ensure the request is being handled.
+		<-c
+		require.Equal(t, 1, task.InFlight())
+
+		c <- struct{}{}
+		require.NoError(t, <-res)
+		require.Equal(t, 0, task.InFlight())
+		require.Eventually(t, func() bool {
+			return task.Idle() == 1
+		}, time.Second, 10*time.Millisecond)
+
+		err := task.Stop(t.Context())
+		require.NoError(t, err)
+		require.ErrorIs(t, <-done, http.ErrServerClosed)
+		require.Eventually(t, func() bool {
+			return task.Idle() == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+	t.Run("stop escalates to close on deadline", func(t *testing.T) {
+		c := make(chan struct{})
+		task, url, done := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			close(c)
+			<-r.Context().Done()
+		})
+		defer let.Halt(task)
+
+		go http.Get(url)
+
+		// Ensure the request is being handled.
+		<-c
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		err := task.Stop(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorIs(t, <-done, http.ErrServerClosed)
+
+		// Connections are closed so the handler returns.
+		require.Eventually(t, func() bool {
+			return task.InFlight() == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestHttpServeMulti(t *testing.T) {
+	listen := func(t *testing.T) net.Listener {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		return l
+	}
+	get := func(t *testing.T, l net.Listener) {
+		res, err := http.Get("http://" + l.Addr().String())
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+	}
+
+	t.Run("serves on all listeners", func(t *testing.T) {
+		a := listen(t)
+		b := listen(t)
+
+		task := let.HttpServeMulti(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})}, a, b)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		get(t, a)
+		get(t, b)
+
+		err := task.Stop(t.Context())
+		require.NoError(t, err)
+		require.ErrorIs(t, <-done, http.ErrServerClosed)
+
+		_, err = http.Get("http://" + b.Addr().String())
+		require.Error(t, err)
+	})
+	t.Run("failure of a listener stops all", func(t *testing.T) {
+		a := listen(t)
+		b := listen(t)
+
+		// Serve on a closed listener fails immediately.
+		b.Close()
+
+		task := let.HttpServeMulti(&http.Server{}, a, b)
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, net.ErrClosed)
+
+		_, err = http.Get("http://" + a.Addr().String())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,24 @@
+package let
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+)
+
+// taskLabel is the pprof label key that holds the name of the Task
+// a goroutine spawned by this package works for.
+// It is shown in goroutine profiles so goroutines can be attributed to their Task.
+const taskLabel = "let.task"
+
+func taskName(t any) string {
+	if n, ok := t.(*named); ok {
+		return n.name
+	}
+	return fmt.Sprintf("%T@%p", t, t)
+}
+
+// goTask runs f in a new goroutine labeled with the name of the given Task.
+func goTask(ctx context.Context, t any, f func(ctx context.Context)) {
+	go pprof.Do(ctx, pprof.Labels(taskLabel, taskName(t)), f)
+}
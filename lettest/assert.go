@@ -0,0 +1,93 @@
+package lettest
+
+import (
+	"testing"
+)
+
+// AssertCalled asserts that the given op was called exactly `n` times.
+func (t *FakeTask) AssertCalled(tb testing.TB, op Op, n int) bool {
+	tb.Helper()
+
+	cs := t.Calls(op)
+	if len(cs) != n {
+		tb.Errorf("expected %s to be called %d times but called %d times", op, n, len(cs))
+		return false
+	}
+	return true
+}
+
+// AssertNotCalled asserts that the given op was never called.
+func (t *FakeTask) AssertNotCalled(tb testing.TB, op Op) bool {
+	tb.Helper()
+	return t.AssertCalled(tb, op, 0)
+}
+
+// AssertStoppedOnce asserts that Stop was called exactly once.
+func (t *FakeTask) AssertStoppedOnce(tb testing.TB) bool {
+	tb.Helper()
+	return t.AssertCalled(tb, OpStop, 1)
+}
+
+// AssertClosedOnce asserts that Close was called exactly once.
+func (t *FakeTask) AssertClosedOnce(tb testing.TB) bool {
+	tb.Helper()
+	return t.AssertCalled(tb, OpClose, 1)
+}
+
+// AssertStoppedBefore asserts that the first Stop on this task began
+// before the first Stop on the other task.
+func (t *FakeTask) AssertStoppedBefore(tb testing.TB, other *FakeTask) bool {
+	tb.Helper()
+	return t.assertBefore(tb, OpStop, other, OpStop)
+}
+
+// AssertClosedBefore asserts that the first Close on this task began
+// before the first Close on the other task.
+func (t *FakeTask) AssertClosedBefore(tb testing.TB, other *FakeTask) bool {
+	tb.Helper()
+	return t.assertBefore(tb, OpClose, other, OpClose)
+}
+
+// AssertWaitReturnedAfterRun asserts that every returned Wait
+// returned after every Run that began before it had returned.
+func (t *FakeTask) AssertWaitReturnedAfterRun(tb testing.TB) bool {
+	tb.Helper()
+
+	runs := t.Calls(OpRun)
+	for _, w := range t.Calls(OpWait) {
+		if !w.Returned() {
+			continue
+		}
+		for _, r := range runs {
+			if r.Begin > w.End {
+				continue
+			}
+			if !r.Returned() || r.End > w.End {
+				tb.Errorf("expected Wait to return after Run but returned while Run is in progress")
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (t *FakeTask) assertBefore(tb testing.TB, op Op, other *FakeTask, other_op Op) bool {
+	tb.Helper()
+
+	a := t.Calls(op)
+	if len(a) == 0 {
+		tb.Errorf("expected %s to be called but never called", op)
+		return false
+	}
+
+	b := other.Calls(other_op)
+	if len(b) == 0 {
+		// Other task is not yet called so this one was called first.
+		return true
+	}
+	if a[0].Begin > b[0].Begin {
+		tb.Errorf("expected %s to be called before %s of the other task", op, other_op)
+		return false
+	}
+	return true
+}
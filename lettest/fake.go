@@ -0,0 +1,177 @@
+// Package lettest provides utilities for testing code built on top of [let].
+package lettest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lesomnus/let"
+)
+
+type Op string
+
+const (
+	OpRun   Op = "Run"
+	OpStop  Op = "Stop"
+	OpClose Op = "Close"
+	OpWait  Op = "Wait"
+)
+
+// Call is a record of a method call on a [FakeTask].
+type Call struct {
+	Op Op
+
+	// Begin and End are sequence numbers shared by all FakeTasks
+	// so the order of calls can be compared across FakeTasks
+	// even if their timestamps are the same.
+	Begin uint64
+	End   uint64
+
+	BeginAt time.Time
+	EndAt   time.Time
+
+	Err error
+}
+
+// Returned reports whether the call has returned.
+func (c Call) Returned() bool {
+	return c.End != 0
+}
+
+var seq atomic.Uint64
+
+// FakeTask is a [let.Task] that records every call to it.
+// By default, its Run returns nil immediately.
+// Use [FakeTask.Block] and [FakeTask.Fail] to script its Run.
+type FakeTask struct {
+	let.Task
+
+	m     sync.Mutex
+	calls []*Call
+
+	err     error
+	block   chan struct{}
+	started chan struct{}
+}
+
+// NewFakeTask creates a FakeTask backed by [let.New].
+func NewFakeTask() *FakeTask {
+	t := &FakeTask{
+		started: make(chan struct{}, 1),
+	}
+	t.Task = let.New(t.body)
+
+	return t
+}
+
+func (t *FakeTask) body(ctx context.Context) error {
+	t.m.Lock()
+	err := t.err
+	block := t.block
+	t.m.Unlock()
+
+	select {
+	case t.started <- struct{}{}:
+	default:
+	}
+
+	if block != nil {
+		select {
+		case <-ctx.Done():
+		case <-block:
+		}
+	}
+
+	return err
+}
+
+// Block makes subsequent Runs block until [FakeTask.Unblock] is called
+// or their context is done.
+func (t *FakeTask) Block() {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.block == nil {
+		t.block = make(chan struct{})
+	}
+}
+
+// Unblock releases the Runs blocked by [FakeTask.Block].
+func (t *FakeTask) Unblock() {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.block != nil {
+		close(t.block)
+		t.block = nil
+	}
+}
+
+// Fail makes subsequent Runs return the given error.
+// Use nil to make them succeed again.
+func (t *FakeTask) Fail(err error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.err = err
+}
+
+// Started returns a channel that receives when a Run enters the task body.
+func (t *FakeTask) Started() <-chan struct{} {
+	return t.started
+}
+
+// Calls returns the calls made so far.
+// If ops are given, only the calls of those ops are returned.
+func (t *FakeTask) Calls(ops ...Op) []Call {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	vs := []Call{}
+	for _, c := range t.calls {
+		if len(ops) > 0 && !slices.Contains(ops, c.Op) {
+			continue
+		}
+		vs = append(vs, *c)
+	}
+
+	return vs
+}
+
+func (t *FakeTask) record(op Op, f func() error) error {
+	c := &Call{
+		Op:      op,
+		Begin:   seq.Add(1),
+		BeginAt: time.Now(),
+	}
+
+	t.m.Lock()
+	t.calls = append(t.calls, c)
+	t.m.Unlock()
+
+	err := f()
+
+	t.m.Lock()
+	c.End = seq.Add(1)
+	c.EndAt = time.Now()
+	c.Err = err
+	t.m.Unlock()
+
+	return err
+}
+
+func (t *FakeTask) Run(ctx context.Context) error {
+	return t.record(OpRun, func() error { return t.Task.Run(ctx) })
+}
+
+func (t *FakeTask) Stop(ctx context.Context) error {
+	return t.record(OpStop, func() error { return t.Task.Stop(ctx) })
+}
+
+func (t *FakeTask) Close() error {
+	return t.record(OpClose, t.Task.Close)
+}
+
+func (t *FakeTask) Wait() error {
+	return t.record(OpWait, t.Task.Wait)
+}
@@ -0,0 +1,74 @@
+package lettest_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/lesomnus/let"
+	"github.com/lesomnus/let/lettest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeTask(t *testing.T) {
+	t.Run("calls are recorded", func(t *testing.T) {
+		task := lettest.NewFakeTask()
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+
+		let.Halt(task)
+
+		cs := task.Calls()
+		require.Len(t, cs, 3)
+		require.Equal(t, lettest.OpRun, cs[0].Op)
+		require.Equal(t, lettest.OpClose, cs[1].Op)
+		require.Equal(t, lettest.OpWait, cs[2].Op)
+		require.Less(t, cs[0].End, cs[1].Begin)
+
+		task.AssertClosedOnce(t)
+		task.AssertNotCalled(t, lettest.OpStop)
+		task.AssertWaitReturnedAfterRun(t)
+	})
+	t.Run("run fails on demand", func(t *testing.T) {
+		task := lettest.NewFakeTask()
+		defer let.Halt(task)
+
+		task.Fail(io.EOF)
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, io.EOF)
+
+		cs := task.Calls(lettest.OpRun)
+		require.Len(t, cs, 1)
+		require.ErrorIs(t, cs[0].Err, io.EOF)
+	})
+	t.Run("run blocks on demand", func(t *testing.T) {
+		task := lettest.NewFakeTask()
+		defer let.Halt(task)
+
+		task.Block()
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		<-task.Started()
+		require.False(t, task.Calls(lettest.OpRun)[0].Returned())
+
+		task.Unblock()
+		require.NoError(t, <-done)
+	})
+	t.Run("stop order across tasks", func(t *testing.T) {
+		a := lettest.NewFakeTask()
+		b := lettest.NewFakeTask()
+
+		task := let.Seq(a, b)
+		defer let.Halt(task)
+
+		task.Stop(t.Context())
+
+		b.AssertStoppedBefore(t, a)
+		a.AssertStoppedOnce(t)
+		b.AssertStoppedOnce(t)
+	})
+}
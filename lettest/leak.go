@@ -0,0 +1,230 @@
+package lettest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Goroutine is a goroutine spawned by [let].
+type Goroutine struct {
+	ID int
+	// Task is the name of the Task the goroutine works for.
+	// It is empty if the goroutine does not work for a Task.
+	// If goroutines of different Tasks have the same stack,
+	// it is the names of the Tasks joined by " or ".
+	Task string
+	// Stack is the stack trace of the goroutine including its creation stack.
+	Stack string
+}
+
+func (g Goroutine) String() string {
+	return fmt.Sprintf("goroutine %d for task %q:\n%s", g.ID, g.Task, g.Stack)
+}
+
+// Snapshot is a set of goroutines that exist at a point in time.
+type Snapshot struct {
+	ids map[int]struct{}
+}
+
+var goroutine_header = regexp.MustCompile(`^goroutine (\d+) \[`)
+
+// taskLabel is the pprof label key [let] labels its goroutines with.
+const taskLabel = "let.task"
+
+func goroutines() []Goroutine {
+	tasks := taskNames()
+
+	var b bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&b, 2)
+
+	gs := []Goroutine{}
+	for _, block := range strings.Split(b.String(), "\n\n") {
+		block = strings.TrimSpace(block)
+		ms := goroutine_header.FindStringSubmatch(block)
+		if ms == nil {
+			continue
+		}
+
+		id, err := strconv.Atoi(ms[1])
+		if err != nil {
+			continue
+		}
+
+		gs = append(gs, Goroutine{
+			ID:    id,
+			Task:  tasks[stackKey(block)],
+			Stack: block,
+		})
+	}
+
+	return gs
+}
+
+// taskNames returns the names of the Tasks by the stacks of the goroutines working for them.
+// Goroutine IDs are not in the profile with labels so goroutines are matched by their stacks.
+// If goroutines of different Tasks have the same stack, their names are joined by " or ".
+func taskNames() map[string]string {
+	var b bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&b, 1)
+
+	names := map[string][]string{}
+	for _, block := range strings.Split(b.String(), "\n\n") {
+		name := ""
+		frames := []string{}
+		for _, line := range strings.Split(block, "\n") {
+			if v, ok := strings.CutPrefix(line, "# labels: "); ok {
+				name = label(v, taskLabel)
+				continue
+			}
+
+			// e.g. "#\t0x4e1518\tmain.block+0x18\t\t/path/to/main.go:10"
+			v, ok := strings.CutPrefix(line, "#\t")
+			if !ok {
+				continue
+			}
+			fs := slices.DeleteFunc(strings.Split(v, "\t"), func(f string) bool { return f == "" })
+			if len(fs) != 3 {
+				continue
+			}
+			if i := strings.LastIndexByte(fs[1], '+'); i >= 0 {
+				frames = append(frames, frame(fs[1][:i], fs[2]))
+			}
+		}
+		if name == "" {
+			continue
+		}
+
+		key := strings.Join(slices.DeleteFunc(frames, func(f string) bool { return f == "" }), "\n")
+		if !slices.Contains(names[key], name) {
+			names[key] = append(names[key], name)
+		}
+	}
+
+	tasks := map[string]string{}
+	for k, vs := range names {
+		tasks[k] = strings.Join(vs, " or ")
+	}
+	return tasks
+}
+
+// stackKey returns the key of the stack trace of a goroutine in the goroutine dump
+// to match it with the goroutine profile.
+func stackKey(block string) string {
+	lines := strings.Split(block, "\n")
+	frames := []string{}
+	for i := 1; i+1 < len(lines); i += 2 {
+		f := lines[i]
+		if strings.HasPrefix(f, "created by ") {
+			break
+		}
+
+		// e.g. "main.block(...)" followed by "\t/path/to/main.go:10 +0x18"
+		if j := strings.LastIndexByte(f, '('); j >= 0 {
+			f = f[:j]
+		}
+		loc, _, _ := strings.Cut(strings.TrimSpace(lines[i+1]), " ")
+		if v := frame(f, loc); v != "" {
+			frames = append(frames, v)
+		}
+	}
+	return strings.Join(frames, "\n")
+}
+
+// frame returns the key of a stack frame.
+// Frames in the runtime are skipped since the goroutine dump hides some of them.
+func frame(f string, loc string) string {
+	if strings.HasPrefix(f, "runtime.") {
+		return ""
+	}
+	return f + " " + loc
+}
+
+// label returns the value of the given key in the labels
+// formatted like `{"k1":"v1", "k2":"v2"}`.
+func label(labels string, key string) string {
+	_, v, ok := strings.Cut(labels, strconv.Quote(key)+":")
+	if !ok {
+		return ""
+	}
+	v, err := strconv.QuotedPrefix(v)
+	if err != nil {
+		return ""
+	}
+	v, err = strconv.Unquote(v)
+	if err != nil {
+		return ""
+	}
+	return v
+}
+
+// spawnedByLet reports whether the goroutine is created by [let].
+func (g Goroutine) spawnedByLet() bool {
+	if g.Task != "" {
+		return true
+	}
+
+	_, created, ok := strings.Cut(g.Stack, "\ncreated by ")
+	return ok && strings.HasPrefix(created, "github.com/lesomnus/let.")
+}
+
+// TakeSnapshot records the goroutines that currently exist.
+func TakeSnapshot() Snapshot {
+	ids := map[int]struct{}{}
+	for _, g := range goroutines() {
+		ids[g.ID] = struct{}{}
+	}
+	return Snapshot{ids}
+}
+
+// Leaks returns the goroutines spawned by [let] after the snapshot was taken
+// that still exist.
+// Since goroutines may take a moment to exit after [let.Halt],
+// it retries until there are no leaks or the context is done.
+func (s Snapshot) Leaks(ctx context.Context) []Goroutine {
+	for {
+		leaks := []Goroutine{}
+		for _, g := range goroutines() {
+			if _, ok := s.ids[g.ID]; ok {
+				continue
+			}
+			if !g.spawnedByLet() {
+				continue
+			}
+			leaks = append(leaks, g)
+		}
+		if len(leaks) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return leaks
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// VerifyNoLeaks fails the test if any goroutine spawned by [let] during the test
+// still exists at the end of the test.
+// Call it at the beginning of the test.
+func VerifyNoLeaks(tb testing.TB) {
+	tb.Helper()
+
+	s := TakeSnapshot()
+	tb.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		for _, g := range s.Leaks(ctx) {
+			tb.Errorf("leaked %s", g)
+		}
+	})
+}
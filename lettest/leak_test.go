@@ -0,0 +1,120 @@
+package lettest_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/lesomnus/let/lettest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaks(t *testing.T) {
+	t.Run("no leaks after halt", func(t *testing.T) {
+		lettest.VerifyNoLeaks(t)
+
+		task := lettest.NewFakeTask()
+		task.Block()
+
+		r := let.NewRunner()
+		go r.Run(t.Context())
+
+		r.Go(task)
+
+		// Ensure the run started.
+		<-task.Started()
+
+		let.Halt(r)
+	})
+	t.Run("leaked goroutine is reported with its task", func(t *testing.T) {
+		s := lettest.TakeSnapshot()
+
+		c := make(chan struct{})
+		defer close(c)
+
+		started := make(chan struct{})
+		task := let.New(func(ctx context.Context) error {
+			// The task ignores its context so it cannot be stopped.
+			close(started)
+			<-c
+			return nil
+		})
+
+		r := let.NewRunner()
+		go r.Run(t.Context())
+
+		r.Go(task)
+
+		// Ensure the run started.
+		<-started
+
+		r.Close()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		leaks := s.Leaks(ctx)
+		require.Len(t, leaks, 1)
+		require.Contains(t, leaks[0].Stack, "created by")
+		require.Contains(t, leaks[0].Task, "*let.task")
+	})
+	t.Run("leaked goroutine is reported with the name of its task", func(t *testing.T) {
+		s := lettest.TakeSnapshot()
+
+		c := make(chan struct{})
+		defer close(c)
+
+		started := make(chan struct{})
+		task := let.Named("foo", let.New(func(ctx context.Context) error {
+			close(started)
+			<-c
+			return nil
+		}))
+
+		r := let.NewRunner()
+		go r.Run(t.Context())
+
+		r.Go(task)
+
+		// Ensure the run started.
+		<-started
+
+		r.Close()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		leaks := s.Leaks(ctx)
+		require.Len(t, leaks, 1)
+		require.Equal(t, "foo", leaks[0].Task)
+	})
+	t.Run("listener goroutines are reported with the http task", func(t *testing.T) {
+		s := lettest.TakeSnapshot()
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		task := let.HttpServeMulti(&http.Server{}, l)
+		defer let.Halt(task)
+		go task.Run(t.Context())
+
+		require.Eventually(t, func() bool {
+			_, err := http.Get("http://" + l.Addr().String())
+			return err == nil
+		}, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		names := []string{}
+		for _, g := range s.Leaks(ctx) {
+			names = append(names, g.Task)
+		}
+		require.Contains(t, strings.Join(names, "\n"), "*let.httpServe@")
+		require.NotContains(t, strings.Join(names, "\n"), "*http.Server@")
+	})
+}
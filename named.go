@@ -0,0 +1,77 @@
+package let
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Info describes a run of a named Task.
+type Info struct {
+	// Name is the name of the Task.
+	Name string
+	// Parent is the run of the closest named Task that runs this Task.
+	Parent *Info
+	// Attempt is the number of Runs of the Task including this one, starting from 1.
+	Attempt int
+	// StartedAt is the time the run started.
+	StartedAt time.Time
+}
+
+// Path returns the names of the Task and its ancestors joined by "/",
+// e.g. "root/api/conn-42".
+func (i *Info) Path() string {
+	names := []string{}
+	for v := i; v != nil; v = v.Parent {
+		names = append(names, v.Name)
+	}
+
+	var b strings.Builder
+	for j := len(names) - 1; j >= 0; j-- {
+		b.WriteString(names[j])
+		if j > 0 {
+			b.WriteByte('/')
+		}
+	}
+	return b.String()
+}
+
+type infoKey struct{}
+
+// FromContext returns the Info of the closest named Task running with the given context.
+// It returns nil if the context is not passed by a named Task.
+func FromContext(ctx context.Context) *Info {
+	v, _ := ctx.Value(infoKey{}).(*Info)
+	return v
+}
+
+type named struct {
+	Task
+	name string
+
+	attempts atomic.Int64
+}
+
+// Named gives a name to the Task.
+// The context passed to the given Task carries the [Info] of the run,
+// which can be retrieved by [FromContext].
+// The name is also used to label the goroutines that run the Task.
+func Named(name string, t Task) Task {
+	n := &named{name: name}
+	n.Task = Wrap(t, func(ctx context.Context, next func(ctx context.Context) error) error {
+		info := &Info{
+			Name:      name,
+			Parent:    FromContext(ctx),
+			Attempt:   int(n.attempts.Add(1)),
+			StartedAt: time.Now(),
+		}
+		return next(context.WithValue(ctx, infoKey{}, info))
+	})
+
+	return n
+}
+
+func (t *named) TryRun(ctx context.Context) error {
+	return TryRun(ctx, t.Task)
+}
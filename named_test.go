@@ -0,0 +1,69 @@
+package let_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamed(t *testing.T) {
+	t.Run("info is carried in context", func(t *testing.T) {
+		var info *let.Info
+		task := let.Named("foo", let.New(func(ctx context.Context) error {
+			info = let.FromContext(ctx)
+			return nil
+		}))
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+		require.NotNil(t, info)
+		require.Equal(t, "foo", info.Name)
+		require.Equal(t, "foo", info.Path())
+		require.Equal(t, 1, info.Attempt)
+		require.False(t, info.StartedAt.IsZero())
+
+		err = task.Run(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, 2, info.Attempt)
+	})
+	t.Run("path of nested tasks", func(t *testing.T) {
+		c := make(chan *let.Info)
+		child := let.Named("conn-42", let.New(func(ctx context.Context) error {
+			c <- let.FromContext(ctx)
+			return nil
+		}))
+
+		r := let.NewRunner()
+		defer let.Halt(r)
+
+		root := let.Named("root", let.Seq(let.Named("api", r)))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			root.Run(t.Context())
+		}()
+		r.Go(child)
+
+		info := <-c
+		require.Equal(t, "root/api/conn-42", info.Path())
+
+		root.Close()
+		<-done
+		let.Halt(root)
+	})
+	t.Run("no info without name", func(t *testing.T) {
+		var info *let.Info
+		task := let.New(func(ctx context.Context) error {
+			info = let.FromContext(ctx)
+			return nil
+		})
+		defer let.Halt(task)
+
+		task.Run(t.Context())
+		require.Nil(t, info)
+	})
+}
@@ -2,6 +2,7 @@ package let
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 )
 
@@ -23,3 +24,19 @@ func (t *once) Run(ctx context.Context) error {
 	defer Halt(t.Task)
 	return t.Task.Run(ctx)
 }
+
+func (t *once) TryRun(ctx context.Context) error {
+	if t.s.Swap(true) {
+		return ErrClosed
+	}
+
+	err := TryRun(ctx, t.Task)
+	if errors.Is(err, ErrBusy) {
+		// The Task is not run so it can be run later.
+		t.s.Store(false)
+		return err
+	}
+
+	Halt(t.Task)
+	return err
+}
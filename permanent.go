@@ -0,0 +1,62 @@
+package let
+
+import (
+	"context"
+	"errors"
+)
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks the error as permanent so it is not retried.
+// It returns nil if `err` is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// IsPermanent reports whether any error in the tree of `err` is marked by [Permanent].
+func IsPermanent(err error) bool {
+	var v *permanentError
+	return errors.As(err, &v)
+}
+
+// Classifier reports whether the error is permanent.
+type Classifier func(err error) bool
+
+type classifierKey struct{}
+
+// Classify reports whether the error is permanent using the [Classifier]
+// of the Runner running with the given context.
+// It uses [IsPermanent] if there is no Classifier.
+func Classify(ctx context.Context, err error) bool {
+	if c, ok := ctx.Value(classifierKey{}).(Classifier); ok {
+		return c(err)
+	}
+	return IsPermanent(err)
+}
+
+type classified struct {
+	Runner
+	c Classifier
+}
+
+// WithClassifier sets the Classifier used by [Classify] for the Tasks run by the given Runner.
+func WithClassifier(c Classifier, r Runner) Runner {
+	return &classified{r, c}
+}
+
+func (r *classified) Run(ctx context.Context) error {
+	return r.Runner.Run(context.WithValue(ctx, classifierKey{}, r.c))
+}
@@ -0,0 +1,61 @@
+package let_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermanent(t *testing.T) {
+	t.Run("nil is not marked", func(t *testing.T) {
+		require.NoError(t, let.Permanent(nil))
+	})
+	t.Run("marked error is permanent", func(t *testing.T) {
+		err := let.Permanent(io.EOF)
+		require.True(t, let.IsPermanent(err))
+		require.ErrorIs(t, err, io.EOF)
+		require.Equal(t, io.EOF.Error(), err.Error())
+
+		err = fmt.Errorf("foo: %w", err)
+		require.True(t, let.IsPermanent(err))
+	})
+	t.Run("unmarked error is not permanent", func(t *testing.T) {
+		require.False(t, let.IsPermanent(io.EOF))
+	})
+}
+
+func TestWithClassifier(t *testing.T) {
+	t.Run("IsPermanent is used by default", func(t *testing.T) {
+		ctx := t.Context()
+		require.True(t, let.Classify(ctx, let.Permanent(io.EOF)))
+		require.False(t, let.Classify(ctx, io.EOF))
+	})
+	t.Run("classifier of the runner is used", func(t *testing.T) {
+		r := let.WithClassifier(func(err error) bool {
+			return errors.Is(err, io.EOF)
+		}, let.NewRunner())
+		defer let.Halt(r)
+
+		c := make(chan bool, 2)
+		done := make(chan struct{})
+		task := let.New(func(ctx context.Context) error {
+			c <- let.Classify(ctx, io.EOF)
+			c <- let.Classify(ctx, let.Permanent(io.ErrUnexpectedEOF))
+			return nil
+		})
+		r.Go(let.Wrap(task, func(ctx context.Context, next func(ctx context.Context) error) error {
+			defer close(done)
+			return next(ctx)
+		}))
+		go r.Run(t.Context())
+
+		<-done
+		require.True(t, <-c)
+		require.False(t, <-c)
+	})
+}
@@ -0,0 +1,59 @@
+package let
+
+import (
+	"context"
+	"sync"
+)
+
+type queued struct {
+	Task
+
+	m sync.Mutex
+	n int
+	l int
+}
+
+// Queue creates a Task that allows at most `n` callers to wait in line
+// while the given Task is running.
+// Run returns [ErrBusy] immediately if the line is full.
+// TryRun returns [ErrBusy] if the Task is running or any caller is waiting.
+// Use `n` of 0 to skip the Run if the Task is already running.
+func Queue(n int, t Task) TryRunner {
+	if n < 0 {
+		panic("n must not be negative")
+	}
+	return &queued{Task: t, l: n + 1}
+}
+
+func (t *queued) acquire(l int) bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.n >= l {
+		return false
+	}
+
+	t.n++
+	return true
+}
+
+func (t *queued) release() {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.n--
+}
+
+func (t *queued) Run(ctx context.Context) error {
+	if !t.acquire(t.l) {
+		return ErrBusy
+	}
+	defer t.release()
+	return t.Task.Run(ctx)
+}
+
+func (t *queued) TryRun(ctx context.Context) error {
+	if !t.acquire(1) {
+		return ErrBusy
+	}
+	defer t.release()
+	return TryRun(ctx, t.Task)
+}
@@ -0,0 +1,79 @@
+package let_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue(t *testing.T) {
+	t.Run("run is skipped if the line is full", func(t *testing.T) {
+		c := make(chan struct{})
+		task := let.Queue(1, let.New(func(ctx context.Context) error {
+			<-c
+			return nil
+		}))
+		defer let.Halt(task)
+
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				task.Run(t.Context())
+			}()
+		}
+
+		// Give the runs a chance to take their place.
+		time.Sleep(10 * time.Millisecond)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrBusy)
+
+		err = task.TryRun(t.Context())
+		require.ErrorIs(t, err, let.ErrBusy)
+
+		c <- struct{}{}
+		c <- struct{}{}
+		wg.Wait()
+	})
+	t.Run("line is freed after the run", func(t *testing.T) {
+		task := let.Queue(0, let.Nop())
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+
+		err = task.TryRun(t.Context())
+		require.NoError(t, err)
+	})
+	t.Run("try run is skipped if the Task is busy", func(t *testing.T) {
+		c := make(chan struct{})
+		base := let.New(func(ctx context.Context) error {
+			<-c
+			<-c
+			return nil
+		})
+		task := let.Queue(0, base)
+		defer let.Halt(task)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			base.Run(t.Context())
+		}()
+
+		// Ensure the run started.
+		c <- struct{}{}
+
+		err := task.TryRun(t.Context())
+		require.ErrorIs(t, err, let.ErrBusy)
+
+		c <- struct{}{}
+		<-done
+	})
+}
@@ -0,0 +1,153 @@
+package let
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limiter blocks until a run is permitted.
+// [golang.org/x/time/rate.Limiter] satisfies this interface.
+type Limiter interface {
+	// Wait blocks until a run is permitted or the context is done.
+	Wait(ctx context.Context) error
+}
+
+type tokenBucket struct {
+	clock Clock
+	rate  float64
+	burst int
+
+	m      sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a Limiter that permits `r` runs per second
+// with bursts of at most `burst` runs.
+// The bucket starts full.
+func NewTokenBucket(r float64, burst int) Limiter {
+	return NewTokenBucketWithClock(RealClock, r, burst)
+}
+
+// NewTokenBucketWithClock is like [NewTokenBucket] but the time is measured by the given Clock.
+func NewTokenBucketWithClock(c Clock, r float64, burst int) Limiter {
+	if r <= 0 {
+		panic("r must be larger than 0")
+	}
+	if burst < 1 {
+		panic("burst must be larger than 0")
+	}
+	return &tokenBucket{
+		clock:  c,
+		rate:   r,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   c.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	now := b.clock.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	b.tokens = min(b.tokens, float64(b.burst))
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) cancel() {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.tokens++
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d := b.reserve()
+	if d == 0 {
+		return nil
+	}
+
+	timer := b.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		// Give the token back so other waiters are not delayed by this one.
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+type rateLimited struct {
+	Task
+	l Limiter
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	forced atomic.Bool
+}
+
+// RateLimit creates a Task that waits for a permit from the given Limiter before each Run.
+// The Limiter can be shared across Tasks.
+// If the Task is stopped or closed while waiting, Run returns [ErrStopped] or [ErrForceClosed].
+func RateLimit(l Limiter, t Task) Task {
+	r := &rateLimited{Task: t, l: l}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	return r
+}
+
+func (t *rateLimited) Run(ctx context.Context) error {
+	if t.ctx.Err() != nil {
+		return t.closedErr()
+	}
+
+	ctx_wait, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(t.ctx, cancel)
+	defer stop()
+
+	if err := t.l.Wait(ctx_wait); err != nil {
+		if t.ctx.Err() != nil {
+			return t.closedErr()
+		}
+		return err
+	}
+
+	return t.Task.Run(ctx)
+}
+
+func (t *rateLimited) closedErr() error {
+	if t.forced.Load() {
+		return ErrForceClosed
+	}
+	return ErrStopped
+}
+
+func (t *rateLimited) Stop(ctx context.Context) error {
+	t.cancel()
+	return t.Task.Stop(ctx)
+}
+
+func (t *rateLimited) Close() error {
+	t.forced.Store(true)
+	t.cancel()
+	return t.Task.Close()
+}
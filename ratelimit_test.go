@@ -0,0 +1,88 @@
+package let_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Run("runs within the burst are not delayed", func(t *testing.T) {
+		l := let.NewTokenBucket(1, 3)
+		task := let.RateLimit(l, let.Nop())
+		defer let.Halt(task)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+
+		for range 3 {
+			err := task.Run(ctx)
+			require.NoError(t, err)
+		}
+
+		err := task.Run(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("limiter is shared across tasks", func(t *testing.T) {
+		l := let.NewTokenBucket(1, 1)
+		a := let.RateLimit(l, let.Nop())
+		b := let.RateLimit(l, let.Nop())
+		defer let.Halt(a)
+		defer let.Halt(b)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+
+		err := a.Run(ctx)
+		require.NoError(t, err)
+
+		err = b.Run(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("tokens are refilled", func(t *testing.T) {
+		c := let.NewFakeClock(time.Time{})
+		l := let.NewTokenBucketWithClock(c, 1, 1)
+		task := let.RateLimit(l, let.Nop())
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		c.BlockUntil(t.Context(), 1)
+		c.Advance(time.Second)
+
+		err = <-done
+		require.NoError(t, err)
+	})
+	t.Run("stop unblocks the wait", func(t *testing.T) {
+		l := let.NewTokenBucket(0.001, 1)
+		task := let.RateLimit(l, let.Nop())
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			task.Stop(t.Context())
+		}()
+
+		err = task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrStopped)
+	})
+	t.Run("run after close returns force closed", func(t *testing.T) {
+		task := let.RateLimit(let.NewTokenBucket(1, 1), let.Nop())
+		task.Close()
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrForceClosed)
+	})
+}
@@ -0,0 +1,20 @@
+package let
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// Recover creates a Task that returns a [PanicError] instead of panicking
+// if the given Task panics in its Run.
+// Panics in goroutines spawned by the given Task are not recovered.
+func Recover(t Task) Task {
+	return Wrap(t, func(ctx context.Context, next func(ctx context.Context) error) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = &PanicError{Value: v, Stack: debug.Stack()}
+			}
+		}()
+		return next(ctx)
+	})
+}
@@ -0,0 +1,55 @@
+package let_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	t.Run("panic is returned as an error", func(t *testing.T) {
+		task := let.Recover(let.New(func(ctx context.Context) error {
+			panic("foo")
+		}))
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrPanicked)
+
+		var panic_err *let.PanicError
+		require.ErrorAs(t, err, &panic_err)
+		require.Equal(t, "foo", panic_err.Value)
+		require.NotEmpty(t, panic_err.Stack)
+	})
+	t.Run("panicked error is unwrapped", func(t *testing.T) {
+		task := let.Recover(let.New(func(ctx context.Context) error {
+			panic(io.EOF)
+		}))
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrPanicked)
+		require.ErrorIs(t, err, io.EOF)
+	})
+	t.Run("task can run after panic", func(t *testing.T) {
+		i := 0
+		task := let.Recover(let.New(func(ctx context.Context) error {
+			i++
+			if i == 1 {
+				panic("foo")
+			}
+			return nil
+		}))
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrPanicked)
+
+		err = task.Run(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, 2, i)
+	})
+}
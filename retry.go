@@ -0,0 +1,62 @@
+package let
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type retried struct {
+	Task
+	c     Clock
+	delay time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Retry creates a Task that runs the given Task again after `delay` if it fails.
+// Run returns once the given Task succeeds, returns [ErrClosed],
+// or fails with an error that [Classify] reports permanent.
+// If the Task is stopped or closed while waiting, Run returns the last error.
+func Retry(delay time.Duration, t Task) Task {
+	return RetryWithClock(RealClock, delay, t)
+}
+
+// RetryWithClock is like [Retry] but the delay is measured by the given Clock.
+func RetryWithClock(c Clock, delay time.Duration, t Task) Task {
+	r := &retried{Task: t, c: clockOr(c), delay: delay}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	return r
+}
+
+func (t *retried) Run(ctx context.Context) error {
+	for {
+		err := t.Task.Run(ctx)
+		if err == nil || errors.Is(err, ErrClosed) || Classify(ctx, err) {
+			return err
+		}
+
+		timer := t.c.NewTimer(t.delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-t.ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C():
+		}
+	}
+}
+
+func (t *retried) Stop(ctx context.Context) error {
+	t.cancel()
+	return t.Task.Stop(ctx)
+}
+
+func (t *retried) Close() error {
+	t.cancel()
+	return t.Task.Close()
+}
@@ -0,0 +1,89 @@
+package let_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	t.Run("retries until success", func(t *testing.T) {
+		clock := let.NewFakeClock(time.Now())
+
+		i := 0
+		task := let.RetryWithClock(clock, time.Second, let.New(func(ctx context.Context) error {
+			i++
+			if i < 3 {
+				return io.EOF
+			}
+			return nil
+		}))
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		for range 2 {
+			err := clock.BlockUntil(t.Context(), 1)
+			require.NoError(t, err)
+			clock.Advance(time.Second)
+		}
+
+		require.NoError(t, <-done)
+		require.Equal(t, 3, i)
+	})
+	t.Run("permanent error is not retried", func(t *testing.T) {
+		i := 0
+		task := let.Retry(time.Hour, let.New(func(ctx context.Context) error {
+			i++
+			return let.Permanent(io.EOF)
+		}))
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, io.EOF)
+		require.True(t, let.IsPermanent(err))
+		require.Equal(t, 1, i)
+	})
+	t.Run("classifier of the runner is used", func(t *testing.T) {
+		i := 0
+		task := let.Retry(time.Hour, let.New(func(ctx context.Context) error {
+			i++
+			return io.EOF
+		}))
+
+		done := make(chan error)
+		r := let.WithClassifier(func(err error) bool {
+			return errors.Is(err, io.EOF)
+		}, let.NewRunner())
+		defer let.Halt(r)
+
+		r.Go(let.Wrap(task, func(ctx context.Context, next func(ctx context.Context) error) error {
+			err := next(ctx)
+			done <- err
+			return err
+		}))
+		go r.Run(t.Context())
+
+		require.ErrorIs(t, <-done, io.EOF)
+		require.Equal(t, 1, i)
+	})
+	t.Run("stop while waiting returns the last error", func(t *testing.T) {
+		task := let.Retry(time.Hour, let.New(func(ctx context.Context) error {
+			return io.EOF
+		}))
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		time.Sleep(10 * time.Millisecond)
+		err := task.Stop(t.Context())
+		require.NoError(t, err)
+		require.ErrorIs(t, <-done, io.EOF)
+	})
+}
@@ -100,7 +100,7 @@ func (r *runner) start(ctx context.Context) {
 }
 
 func (*runner) run(t Task, ctx context.Context) {
-	go t.Run(ctx)
+	goTask(ctx, t, func(ctx context.Context) { t.Run(ctx) })
 }
 
 func (r *runner) Run(ctx context.Context) error {
@@ -129,7 +129,7 @@ func (r *runner) stopTasks() {
 
 	errs := []error{}
 	for _, t := range slices.Backward(r.tasks) {
-		if err := t.Stop(ctx); err != nil && err != ErrClosed {
+		if err := t.Stop(ctx); err != nil && !errors.Is(err, ErrClosed) {
 			errs = append(errs, err)
 		}
 	}
@@ -139,12 +139,12 @@ func (r *runner) stopTasks() {
 
 func (r *runner) Stop(ctx context.Context) error {
 	if !r.stop() {
-		go r.stopTasks()
+		goTask(ctx, r, func(context.Context) { r.stopTasks() })
 	}
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return stopErr(ctx)
 	case <-r.stop_done:
 		return r.stop_err
 	}
@@ -158,7 +158,7 @@ func (r *runner) Close() error {
 
 	errs := []error{}
 	for _, t := range slices.Backward(r.tasks) {
-		if err := t.Close(); err != nil && err != ErrClosed {
+		if err := t.Close(); err != nil && !errors.Is(err, ErrClosed) {
 			errs = append(errs, err)
 		}
 	}
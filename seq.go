@@ -22,7 +22,7 @@ func Seq(ts ...Task) Task {
 			if err == nil {
 				continue
 			}
-			if err == ErrClosed {
+			if errors.Is(err, ErrClosed) {
 				// The step was closed, so stop the sequence.
 				return nil
 			}
@@ -2,6 +2,7 @@ package let_test
 
 import (
 	"context"
+	"io"
 	"testing"
 
 	"github.com/lesomnus/let"
@@ -71,4 +72,20 @@ func TestSeq(t *testing.T) {
 		v = <-c
 		require.Equal(t, 42, v)
 	})
+	t.Run("dependency failure is not ignored", func(t *testing.T) {
+		task := let.Seq(
+			let.New(func(ctx context.Context) error {
+				return &let.DependencyError{Err: io.EOF}
+			}),
+			let.New(func(ctx context.Context) error {
+				t.Fail()
+				return nil
+			}),
+		)
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrDependencyFailed)
+		require.ErrorIs(t, err, io.EOF)
+	})
 }
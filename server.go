@@ -0,0 +1,112 @@
+package let
+
+import (
+	"context"
+	"sync"
+)
+
+// Servable is a server that serves until it is shut down or closed,
+// like [net/http.Server].
+type Servable interface {
+	// Serve blocks until the server stops.
+	Serve() error
+	// Shutdown gracefully stops the server until the context is done.
+	Shutdown(ctx context.Context) error
+	// Close stops the server immediately.
+	Close() error
+}
+
+type server struct {
+	base Task
+
+	graceful func(ctx context.Context) error
+	force    func() error
+
+	done   chan struct{}
+	finish func()
+
+	stop_once sync.Once
+	stop_err  error
+	stop_done chan struct{}
+
+	close_once sync.Once
+	close_err  error
+}
+
+// Server creates a Task that runs `serve` like [New] does.
+// Stop calls `graceful` with the context given to Stop
+// and calls `force` if the context is done before `graceful` returns,
+// which must make `graceful` return.
+// Close calls `force`.
+// `serve` is run only once; subsequent Runs return [ErrClosed].
+func Server(serve func(ctx context.Context) error, graceful func(ctx context.Context) error, force func() error) Task {
+	done := make(chan struct{})
+	return &server{
+		base: Once(New(serve)),
+
+		graceful: graceful,
+		force:    force,
+
+		done:   done,
+		finish: sync.OnceFunc(func() { close(done) }),
+
+		stop_done: make(chan struct{}),
+	}
+}
+
+// ServerOf creates a Task that runs the given server as [Server] does.
+func ServerOf(s Servable) Task {
+	return Server(func(ctx context.Context) error {
+		return s.Serve()
+	}, s.Shutdown, s.Close)
+}
+
+func (t *server) Run(ctx context.Context) error {
+	return t.base.Run(ctx)
+}
+
+func (t *server) stop(ctx context.Context) {
+	t.stop_once.Do(func() {
+		// Stop may be waited after Close finishes the Task
+		// so notify the result of the stop separately.
+		defer close(t.stop_done)
+
+		t.stop_err = t.graceful(ctx)
+		if t.stop_err != nil && ctx.Err() != nil {
+			// Drain deadline exceeded so stop the server forcefully.
+			t.close()
+			return
+		}
+
+		t.base.Stop(ctx)
+		t.finish()
+	})
+}
+
+func (t *server) Stop(ctx context.Context) error {
+	goTask(ctx, t, t.stop)
+	select {
+	case <-ctx.Done():
+		return stopErr(ctx)
+	case <-t.stop_done:
+		return t.stop_err
+	}
+}
+
+func (t *server) close() {
+	t.close_once.Do(func() {
+		t.close_err = t.force()
+		t.base.Close()
+		t.finish()
+	})
+}
+
+func (t *server) Close() error {
+	t.close()
+	return t.close_err
+}
+
+func (t *server) Wait() error {
+	<-t.done
+	return t.base.Wait()
+}
@@ -0,0 +1,165 @@
+package let_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeServable struct {
+	serving chan struct{}
+	drained chan struct{}
+	stopped chan struct{}
+	stop    func()
+
+	m     sync.Mutex
+	calls []string
+}
+
+func newFakeServable() *fakeServable {
+	stopped := make(chan struct{})
+	return &fakeServable{
+		serving: make(chan struct{}),
+		drained: make(chan struct{}),
+		stopped: stopped,
+		stop:    sync.OnceFunc(func() { close(stopped) }),
+	}
+}
+
+func (s *fakeServable) record(v string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.calls = append(s.calls, v)
+}
+
+func (s *fakeServable) Calls() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]string{}, s.calls...)
+}
+
+func (s *fakeServable) Serve() error {
+	close(s.serving)
+	<-s.stopped
+	return io.EOF
+}
+
+func (s *fakeServable) Shutdown(ctx context.Context) error {
+	s.record("shutdown")
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.drained:
+		s.stop()
+		return nil
+	case <-s.stopped:
+		return nil
+	}
+}
+
+func (s *fakeServable) Close() error {
+	s.record("close")
+	s.stop()
+	return nil
+}
+
+func TestServer(t *testing.T) {
+	t.Run("stop shuts down the server", func(t *testing.T) {
+		s := newFakeServable()
+		close(s.drained)
+
+		task := let.ServerOf(s)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		// Ensure the server is serving.
+		<-s.serving
+
+		err := task.Stop(t.Context())
+		require.NoError(t, err)
+		require.ErrorIs(t, <-done, io.EOF)
+		require.Equal(t, []string{"shutdown"}, s.Calls())
+
+		err = task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrClosed)
+	})
+	t.Run("stop closes the server on deadline", func(t *testing.T) {
+		s := newFakeServable()
+		task := let.ServerOf(s)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		// Ensure the server is serving.
+		<-s.serving
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		err := task.Stop(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorIs(t, <-done, io.EOF)
+
+		task.Wait()
+		require.Equal(t, []string{"shutdown", "close"}, s.Calls())
+	})
+	t.Run("close closes the server", func(t *testing.T) {
+		s := newFakeServable()
+		task := let.ServerOf(s)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		// Ensure the server is serving.
+		<-s.serving
+
+		err := task.Close()
+		require.NoError(t, err)
+		require.ErrorIs(t, <-done, io.EOF)
+
+		task.Wait()
+		require.Equal(t, []string{"close"}, s.Calls())
+	})
+	t.Run("close during stop", func(t *testing.T) {
+		s := newFakeServable()
+		task := let.ServerOf(s)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		// Ensure the server is serving.
+		<-s.serving
+
+		stopped := make(chan error)
+		go func() {
+			stopped <- task.Stop(t.Context())
+		}()
+		require.Eventually(t, func() bool {
+			return len(s.Calls()) > 0
+		}, time.Second, time.Millisecond)
+
+		err := task.Close()
+		require.NoError(t, err)
+		require.NoError(t, <-stopped)
+		require.ErrorIs(t, <-done, io.EOF)
+
+		task.Wait()
+		require.Equal(t, []string{"shutdown", "close"}, s.Calls())
+	})
+}
@@ -6,9 +6,17 @@ import (
 )
 
 func Sleep(d time.Duration) Task {
+	return SleepWithClock(RealClock, d)
+}
+
+// SleepWithClock is like [Sleep] but the time is measured by the given Clock.
+func SleepWithClock(c Clock, d time.Duration) Task {
 	return New(func(ctx context.Context) error {
+		timer := c.NewTimer(d)
+		defer timer.Stop()
+
 		select {
-		case <-time.After(d):
+		case <-timer.C():
 			return nil
 		case <-ctx.Done():
 			return ctx.Err()
@@ -0,0 +1,163 @@
+package let
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Stage creates a Task that applies `fn` to the values received from `in`
+// using `workers` goroutines and sends the results to the returned channel.
+// The returned channel is closed when the Run returns.
+// Run returns when `in` is closed, the Task is stopped, or `fn` returns an error.
+// Stop finishes the values in flight without receiving new ones
+// and Close cancels the context passed to `fn`.
+// If the context of Run is done or the Task is closed, Run returns the error of the context.
+// The Task can be run only once; subsequent Runs return [ErrClosed].
+func Stage[In, Out any](in <-chan In, workers int, fn func(ctx context.Context, v In) (Out, error)) (Task, <-chan Out) {
+	if workers < 1 {
+		panic("workers must be larger than 0")
+	}
+
+	out := make(chan Out)
+	t := NewGraceful(0, func(ctx context.Context) error {
+		defer close(out)
+
+		parent := ctx
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		var (
+			m      sync.Mutex
+			failed error
+		)
+
+		stopping := Stopping(ctx)
+
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stopping:
+						return
+					default:
+					}
+
+					var v In
+					select {
+					case <-stopping:
+						return
+					case <-ctx.Done():
+						return
+					case w, ok := <-in:
+						if !ok {
+							return
+						}
+						v = w
+					}
+
+					w, err := fn(ctx, v)
+					if err != nil {
+						m.Lock()
+						if failed == nil {
+							failed = err
+						}
+						m.Unlock()
+
+						cancel(err)
+						return
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case out <- w:
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		if failed != nil {
+			return failed
+		}
+		return parent.Err()
+	})
+
+	return Once(t), out
+}
+
+type pipeline struct {
+	Task
+	stages []Task
+}
+
+// Pipeline creates a Task that runs the given stages, created by [Stage], concurrently.
+// The stages must be given in order so that each stage receives from the output of the previous one.
+// Stop stops the stages one by one in order, so each stage drains
+// the output of the previous stage before it stops.
+// If any stage fails, all the stages are closed and the error is returned.
+func Pipeline(stages ...Task) Task {
+	p := &pipeline{stages: stages}
+	p.Task = NewGraceful(0, func(ctx context.Context) error {
+		// Stages are stopped by the pipeline one by one,
+		// so the stopping signal of the pipeline must not reach them at once.
+		ctx = withoutStopping(ctx)
+
+		errs := make(chan error, len(stages))
+		for _, s := range stages {
+			goTask(ctx, s, func(ctx context.Context) { errs <- s.Run(ctx) })
+		}
+
+		var err error
+		for range stages {
+			e := <-errs
+			if e == nil || errors.Is(e, ErrClosed) || err != nil {
+				continue
+			}
+
+			err = e
+			for _, s := range stages {
+				s.Close()
+			}
+		}
+
+		return err
+	})
+
+	return p
+}
+
+func (t *pipeline) Stop(ctx context.Context) error {
+	// Stop of a stage returns after its Run returns, which closes its output,
+	// so the next stage has received all the values when it is stopped.
+	for _, s := range t.stages {
+		if err := s.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	return t.Task.Stop(ctx)
+}
+
+func (t *pipeline) Close() error {
+	errs := make([]error, 0, len(t.stages))
+	for _, s := range t.stages {
+		errs = append(errs, s.Close())
+	}
+
+	t.Task.Close()
+	return errors.Join(errs...)
+}
+
+func (t *pipeline) Wait() error {
+	errs := make([]error, 0, len(t.stages))
+	for _, s := range t.stages {
+		errs = append(errs, s.Wait())
+	}
+
+	errs = append(errs, t.Task.Wait())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,249 @@
+package let_test
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStage(t *testing.T) {
+	t.Run("processes all the values", func(t *testing.T) {
+		in := make(chan int)
+		task, out := let.Stage(in, 3, func(ctx context.Context, v int) (int, error) {
+			return v * 2, nil
+		})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+		go func() {
+			defer close(in)
+			for i := range 10 {
+				in <- i
+			}
+		}()
+
+		vs := []int{}
+		for v := range out {
+			vs = append(vs, v)
+		}
+		slices.Sort(vs)
+		require.Equal(t, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}, vs)
+		require.NoError(t, <-done)
+	})
+	t.Run("stop finishes values in flight", func(t *testing.T) {
+		in := make(chan int, 2)
+		in <- 1
+		in <- 2
+
+		c := make(chan struct{})
+		task, out := let.Stage(in, 1, func(ctx context.Context, v int) (int, error) {
+			close(c)
+			<-let.Stopping(ctx)
+			return v, ctx.Err()
+		})
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+		<-c
+
+		stopped := make(chan error)
+		go func() { stopped <- task.Stop(t.Context()) }()
+
+		vs := []int{}
+		for v := range out {
+			vs = append(vs, v)
+		}
+		require.Equal(t, []int{1}, vs)
+		require.NoError(t, <-done)
+		require.NoError(t, <-stopped)
+		require.Len(t, in, 1)
+	})
+	t.Run("error is returned", func(t *testing.T) {
+		in := make(chan int, 1)
+		in <- 1
+
+		task, out := let.Stage(in, 2, func(ctx context.Context, v int) (int, error) {
+			return 0, io.EOF
+		})
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, io.EOF)
+
+		_, ok := <-out
+		require.False(t, ok)
+	})
+	t.Run("done context is reported", func(t *testing.T) {
+		in := make(chan int)
+		task, _ := let.Stage(in, 1, func(ctx context.Context, v int) (int, error) {
+			return v, nil
+		})
+		defer let.Halt(task)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan error)
+		go func() { done <- task.Run(ctx) }()
+
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+	t.Run("runs only once", func(t *testing.T) {
+		in := make(chan int)
+		close(in)
+
+		task, _ := let.Stage(in, 1, func(ctx context.Context, v int) (int, error) {
+			return v, nil
+		})
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+
+		err = task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrClosed)
+	})
+}
+
+func TestPipeline(t *testing.T) {
+	t.Run("values flow through the stages", func(t *testing.T) {
+		in := make(chan int)
+		s1, c1 := let.Stage(in, 2, func(ctx context.Context, v int) (int, error) {
+			return v + 1, nil
+		})
+		s2, c2 := let.Stage(c1, 2, func(ctx context.Context, v int) (string, error) {
+			return strconv.Itoa(v), nil
+		})
+
+		task := let.Pipeline(s1, s2)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+		go func() {
+			defer close(in)
+			for i := range 3 {
+				in <- i
+			}
+		}()
+
+		vs := []string{}
+		for v := range c2 {
+			vs = append(vs, v)
+		}
+		slices.Sort(vs)
+		require.Equal(t, []string{"1", "2", "3"}, vs)
+		require.NoError(t, <-done)
+	})
+	t.Run("stop drains the stages", func(t *testing.T) {
+		in := make(chan int)
+		s1, c1 := let.Stage(in, 1, func(ctx context.Context, v int) (int, error) {
+			return v, nil
+		})
+		s2, c2 := let.Stage(c1, 1, func(ctx context.Context, v int) (int, error) {
+			return v, nil
+		})
+
+		task := let.Pipeline(s1, s2)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		in <- 42
+		stopped := make(chan error)
+		go func() { stopped <- task.Stop(t.Context()) }()
+
+		require.Equal(t, 42, <-c2)
+		_, ok := <-c2
+		require.False(t, ok)
+		require.NoError(t, <-done)
+		require.NoError(t, <-stopped)
+
+		err := let.Halt(task)
+		require.NoError(t, err)
+	})
+	t.Run("stop drains many stages with many workers", func(t *testing.T) {
+		in := make(chan int)
+		accepted := atomic.Int32{}
+		s0, c := let.Stage(in, 3, func(ctx context.Context, v int) (int, error) {
+			accepted.Add(1)
+			return v, nil
+		})
+
+		stages := []let.Task{s0}
+		for range 3 {
+			var s let.Task
+			s, c = let.Stage(c, 3, func(ctx context.Context, v int) (int, error) {
+				// Slow stages keep the values in flight between the stages.
+				time.Sleep(time.Millisecond)
+				return v, nil
+			})
+			stages = append(stages, s)
+		}
+
+		task := let.Pipeline(stages...)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		quit := make(chan struct{})
+		defer close(quit)
+		go func() {
+			for i := 0; ; i++ {
+				select {
+				case <-quit:
+					return
+				case in <- i:
+				}
+			}
+		}()
+
+		received := make(chan int)
+		go func() {
+			n := 0
+			for range c {
+				n++
+			}
+			received <- n
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 2*time.Second)
+		defer cancel()
+
+		err := task.Stop(ctx)
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+		require.Equal(t, int(accepted.Load()), <-received)
+
+		err = let.Halt(task)
+		require.NoError(t, err)
+	})
+	t.Run("failure closes all the stages", func(t *testing.T) {
+		in := make(chan int)
+		s1, c1 := let.Stage(in, 1, func(ctx context.Context, v int) (int, error) {
+			return v, nil
+		})
+		s2, _ := let.Stage(c1, 1, func(ctx context.Context, v int) (int, error) {
+			return 0, io.EOF
+		})
+
+		task := let.Pipeline(s1, s2)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+
+		in <- 1
+		require.ErrorIs(t, <-done, io.EOF)
+	})
+}
@@ -0,0 +1,80 @@
+package let
+
+import (
+	"context"
+	"time"
+)
+
+type stoppingKey struct{}
+
+// withStopping returns a context carrying a stopping signal that is done
+// when the given `stopping`, the stopping signal of `ctx`, or `ctx` itself is done.
+// The cause of the given `stopping` is propagated to the signal.
+func withStopping(ctx context.Context, stopping context.Context) (context.Context, context.CancelFunc) {
+	s, notify := context.WithCancelCause(ctx)
+	propagate := func(p context.Context) func() bool {
+		return context.AfterFunc(p, func() { notify(context.Cause(p)) })
+	}
+
+	stop := propagate(stopping)
+	stop_parent := func() bool { return false }
+	if p, ok := ctx.Value(stoppingKey{}).(context.Context); ok {
+		stop_parent = propagate(p)
+	}
+
+	return context.WithValue(ctx, stoppingKey{}, s), func() {
+		stop()
+		stop_parent()
+		notify(nil)
+	}
+}
+
+// withoutStopping returns a context that does not carry the stopping signal of `ctx`.
+func withoutStopping(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stoppingKey{}, nil)
+}
+
+// stoppedGracefully reports whether the stopping signal of the context
+// is done by Stop rather than Close or the cancel of the context.
+func stoppedGracefully(ctx context.Context) bool {
+	s, ok := ctx.Value(stoppingKey{}).(context.Context)
+	return ok && context.Cause(s) == ErrStopped
+}
+
+// stoppingCause returns the cause of the stopping signal of the context.
+func stoppingCause(ctx context.Context) error {
+	if s, ok := ctx.Value(stoppingKey{}).(context.Context); ok {
+		return context.Cause(s)
+	}
+	return context.Cause(ctx)
+}
+
+// Stopping returns a channel that is closed when the Task running with the given context,
+// or any Task running it, is requested to stop gracefully.
+// For the Task created by [NewGraceful], the context is not canceled by Stop,
+// so the body can finish its current unit of work after the channel is closed.
+// It returns `ctx.Done()` if the context is not passed by a Task.
+func Stopping(ctx context.Context) <-chan struct{} {
+	if s, ok := ctx.Value(stoppingKey{}).(context.Context); ok {
+		return s.Done()
+	}
+	return ctx.Done()
+}
+
+// NewGraceful is like [New] but Stop does not cancel the context passed to the body.
+// Instead, [Stopping] of the context is closed on Stop and the context is canceled
+// on Close or after the `grace` period since Stop.
+// If `grace` is not positive, the context is canceled only on Close.
+func NewGraceful(grace time.Duration, f func(ctx context.Context) error) Task {
+	return NewGracefulWithClock(RealClock, grace, f)
+}
+
+// NewGracefulWithClock is like [NewGraceful] but the grace period is measured by the given Clock.
+func NewGracefulWithClock(c Clock, grace time.Duration, f func(ctx context.Context) error) Task {
+	t := NewWithContext(context.Background(), f).(*task)
+	t.graceful = true
+	t.grace = grace
+	t.clock = clockOr(c)
+
+	return t
+}
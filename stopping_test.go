@@ -0,0 +1,138 @@
+package let_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopping(t *testing.T) {
+	t.Run("closed on stop", func(t *testing.T) {
+		c := make(chan struct{})
+		task := let.New(func(ctx context.Context) error {
+			close(c)
+			<-let.Stopping(ctx)
+			<-ctx.Done()
+			return nil
+		})
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+		<-c
+
+		err := task.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+	})
+	t.Run("context done without task", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		select {
+		case <-let.Stopping(ctx):
+		default:
+			require.FailNow(t, "expected to be closed")
+		}
+	})
+	t.Run("closed when the parent stops", func(t *testing.T) {
+		c := make(chan struct{})
+		inner := let.New(func(ctx context.Context) error {
+			close(c)
+			<-let.Stopping(ctx)
+			return nil
+		})
+		defer let.Halt(inner)
+
+		outer := let.NewGraceful(0, func(ctx context.Context) error {
+			return inner.Run(ctx)
+		})
+
+		done := make(chan error)
+		go func() { done <- outer.Run(t.Context()) }()
+		<-c
+
+		err := outer.Stop(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+	})
+}
+
+func TestNewGraceful(t *testing.T) {
+	t.Run("stop does not cancel the context", func(t *testing.T) {
+		c := make(chan struct{})
+		next := make(chan struct{})
+		task := let.NewGraceful(0, func(ctx context.Context) error {
+			close(c)
+			<-let.Stopping(ctx)
+			require.NoError(t, ctx.Err())
+
+			<-next
+			return ctx.Err()
+		})
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+		<-c
+
+		stopped := make(chan error)
+		go func() { stopped <- task.Stop(t.Context()) }()
+
+		select {
+		case <-stopped:
+			require.FailNow(t, "Stop must wait for the body")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		close(next)
+		require.NoError(t, <-done)
+		require.NoError(t, <-stopped)
+	})
+	t.Run("close cancels the context", func(t *testing.T) {
+		c := make(chan struct{})
+		task := let.NewGraceful(0, func(ctx context.Context) error {
+			close(c)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+		<-c
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		err := task.Stop(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		task.Close()
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+	t.Run("context is canceled after grace period", func(t *testing.T) {
+		clock := let.NewFakeClock(time.Now())
+
+		c := make(chan struct{})
+		task := let.NewGracefulWithClock(clock, time.Second, func(ctx context.Context) error {
+			close(c)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		done := make(chan error)
+		go func() { done <- task.Run(t.Context()) }()
+		<-c
+
+		stopped := make(chan error)
+		go func() { stopped <- task.Stop(t.Context()) }()
+
+		err := clock.BlockUntil(t.Context(), 1)
+		require.NoError(t, err)
+		clock.Advance(time.Second)
+
+		require.ErrorIs(t, <-done, context.Canceled)
+		require.NoError(t, <-stopped)
+	})
+}
@@ -0,0 +1,98 @@
+//go:build unix
+
+// Package systemd integrates [let] Tasks with systemd
+// through socket activation and the sd_notify protocol.
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// listen_fds_start is the first file descriptor passed by systemd.
+var listen_fds_start = 3
+
+// Files returns the files passed by systemd socket activation
+// along with their names given by `FileDescriptorName=`.
+// It returns nil if no files are passed to this process.
+// The environment variables for socket activation are unset
+// so the files are not inherited by child processes.
+func Files() ([]*os.File, error) {
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+	defer os.Unsetenv("LISTEN_FDNAMES")
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LISTEN_FDS: %w", err)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	fs := make([]*os.File, 0, n)
+	for i := range n {
+		fd := listen_fds_start + i
+		syscall.CloseOnExec(fd)
+
+		name := "LISTEN_FD_" + strconv.Itoa(fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		fs = append(fs, os.NewFile(uintptr(fd), name))
+	}
+
+	return fs, nil
+}
+
+// Listeners returns the listeners passed by systemd socket activation
+// in the order of the sockets in the socket unit.
+// An entry is nil if the file is not a stream socket.
+// The listeners can be used with [let.HttpServe], [let.GrpcServe], or [let.ServeConn].
+func Listeners() ([]net.Listener, error) {
+	fs, err := Files()
+	if err != nil {
+		return nil, err
+	}
+
+	ls := make([]net.Listener, len(fs))
+	for i, f := range fs {
+		// The listener holds its own duplicate of the file descriptor.
+		if l, err := net.FileListener(f); err == nil {
+			ls[i] = l
+			f.Close()
+		}
+	}
+
+	return ls, nil
+}
+
+// ListenersByName is like [Listeners] but groups the listeners by their names
+// given by `FileDescriptorName=`.
+func ListenersByName() (map[string][]net.Listener, error) {
+	fs, err := Files()
+	if err != nil {
+		return nil, err
+	}
+
+	ls := map[string][]net.Listener{}
+	for _, f := range fs {
+		if l, err := net.FileListener(f); err == nil {
+			ls[f.Name()] = append(ls[f.Name()], l)
+			f.Close()
+		}
+	}
+
+	return ls, nil
+}
@@ -0,0 +1,88 @@
+//go:build unix
+
+package systemd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// passListeners places the files of the given listeners at consecutive file descriptors
+// as systemd does and sets the environment variables for socket activation.
+func passListeners(t *testing.T, names string, ls ...net.Listener) {
+	const start = 100
+
+	for i, l := range ls {
+		f, err := l.(*net.TCPListener).File()
+		require.NoError(t, err)
+
+		err = syscall.Dup2(int(f.Fd()), start+i)
+		require.NoError(t, err)
+		f.Close()
+	}
+
+	prev := listen_fds_start
+	listen_fds_start = start
+	t.Cleanup(func() { listen_fds_start = prev })
+
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", strconv.Itoa(len(ls)))
+	t.Setenv("LISTEN_FDNAMES", names)
+}
+
+func listen(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func TestListeners(t *testing.T) {
+	t.Run("listeners are passed", func(t *testing.T) {
+		a := listen(t)
+		b := listen(t)
+		passListeners(t, "", a, b)
+
+		ls, err := Listeners()
+		require.NoError(t, err)
+		require.Len(t, ls, 2)
+		require.Equal(t, a.Addr().String(), ls[0].Addr().String())
+		require.Equal(t, b.Addr().String(), ls[1].Addr().String())
+		for _, l := range ls {
+			l.Close()
+		}
+
+		_, ok := os.LookupEnv("LISTEN_FDS")
+		require.False(t, ok)
+	})
+	t.Run("listeners are grouped by name", func(t *testing.T) {
+		a := listen(t)
+		b := listen(t)
+		c := listen(t)
+		passListeners(t, "http:admin:http", a, b, c)
+
+		ls, err := ListenersByName()
+		require.NoError(t, err)
+		require.Len(t, ls["http"], 2)
+		require.Len(t, ls["admin"], 1)
+		require.Equal(t, b.Addr().String(), ls["admin"][0].Addr().String())
+		for _, vs := range ls {
+			for _, l := range vs {
+				l.Close()
+			}
+		}
+	})
+	t.Run("listeners for other process are ignored", func(t *testing.T) {
+		t.Setenv("LISTEN_PID", strconv.Itoa(1<<30))
+		t.Setenv("LISTEN_FDS", "1")
+
+		ls, err := Listeners()
+		require.NoError(t, err)
+		require.Empty(t, ls)
+	})
+}
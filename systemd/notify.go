@@ -0,0 +1,122 @@
+//go:build unix
+
+package systemd
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lesomnus/let"
+)
+
+const (
+	Ready     = "READY=1"
+	Stopping  = "STOPPING=1"
+	Reloading = "RELOADING=1"
+	Watchdog  = "WATCHDOG=1"
+)
+
+// Notify sends the given states to the socket in `NOTIFY_SOCKET`.
+// It reports false if `NOTIFY_SOCKET` is not set,
+// which means the process is not supervised by systemd.
+func Notify(states ...string) (bool, error) {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return false, nil
+	}
+	if strings.HasPrefix(addr, "@") {
+		// Abstract socket.
+		addr = "\x00" + addr[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(strings.Join(states, "\n"))); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// WithNotify creates a Task that notifies [Ready] when the given Task starts to Run
+// and [Stopping] when it is stopped or closed.
+// Errors from the notification are ignored.
+func WithNotify(t let.Task) let.Task {
+	return &notified{
+		Task: let.Wrap(t, func(ctx context.Context, next func(ctx context.Context) error) error {
+			Notify(Ready)
+			return next(ctx)
+		}),
+	}
+}
+
+type notified struct {
+	let.Task
+}
+
+func (t *notified) Stop(ctx context.Context) error {
+	Notify(Stopping)
+	return t.Task.Stop(ctx)
+}
+
+func (t *notified) Close() error {
+	Notify(Stopping)
+	return t.Task.Close()
+}
+
+// WatchdogInterval returns the interval at which the watchdog must be notified,
+// which is the half of `WATCHDOG_USEC`.
+// It reports false if the watchdog is not enabled for this process.
+func WatchdogInterval() (time.Duration, bool) {
+	pid := os.Getenv("WATCHDOG_PID")
+	if pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+
+	return time.Duration(usec) * time.Microsecond / 2, true
+}
+
+// NewWatchdog creates a Task that notifies [Watchdog] every [WatchdogInterval]
+// until it is stopped.
+// It reports false if the watchdog is not enabled for this process.
+func NewWatchdog() (let.Task, bool) {
+	return NewWatchdogWithClock(let.RealClock)
+}
+
+// NewWatchdogWithClock is like [NewWatchdog] but the interval is measured by the given Clock.
+func NewWatchdogWithClock(c let.Clock) (let.Task, bool) {
+	d, ok := WatchdogInterval()
+	if !ok {
+		return nil, false
+	}
+
+	return let.New(func(ctx context.Context) error {
+		ticker := c.NewTicker(d)
+		defer ticker.Stop()
+
+		for {
+			if _, err := Notify(Watchdog); err != nil {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C():
+			}
+		}
+	}), true
+}
@@ -0,0 +1,110 @@
+//go:build unix
+
+package systemd_test
+
+import (
+	"net"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/lesomnus/let/systemd"
+	"github.com/stretchr/testify/require"
+)
+
+func listenNotify(t *testing.T) *net.UnixConn {
+	addr := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", addr)
+	return conn
+}
+
+func receive(t *testing.T, conn *net.UnixConn) string {
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	b := make([]byte, 256)
+	n, err := conn.Read(b)
+	require.NoError(t, err)
+	return string(b[:n])
+}
+
+func TestNotify(t *testing.T) {
+	t.Run("states are sent", func(t *testing.T) {
+		conn := listenNotify(t)
+
+		ok, err := systemd.Notify(systemd.Ready, "STATUS=foo")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "READY=1\nSTATUS=foo", receive(t, conn))
+	})
+	t.Run("no-op without notify socket", func(t *testing.T) {
+		t.Setenv("NOTIFY_SOCKET", "")
+
+		ok, err := systemd.Notify(systemd.Ready)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+	t.Run("task run and stop are notified", func(t *testing.T) {
+		conn := listenNotify(t)
+
+		task := systemd.WithNotify(let.Nop())
+		defer let.Halt(task)
+
+		err := task.Run(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, systemd.Ready, receive(t, conn))
+
+		err = task.Stop(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, systemd.Stopping, receive(t, conn))
+	})
+}
+
+func TestWatchdog(t *testing.T) {
+	t.Run("disabled without watchdog usec", func(t *testing.T) {
+		t.Setenv("WATCHDOG_USEC", "")
+
+		_, ok := systemd.NewWatchdog()
+		require.False(t, ok)
+	})
+	t.Run("watchdog is notified periodically", func(t *testing.T) {
+		conn := listenNotify(t)
+		t.Setenv("WATCHDOG_USEC", "2000000")
+		t.Setenv("WATCHDOG_PID", "")
+
+		d, ok := systemd.WatchdogInterval()
+		require.True(t, ok)
+		require.Equal(t, time.Second, d)
+
+		c := let.NewFakeClock(time.Time{})
+		task, ok := systemd.NewWatchdogWithClock(c)
+		require.True(t, ok)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		require.Equal(t, systemd.Watchdog, receive(t, conn))
+
+		c.BlockUntil(t.Context(), 1)
+		c.Advance(time.Second)
+		require.Equal(t, systemd.Watchdog, receive(t, conn))
+
+		task.Stop(t.Context())
+		require.NoError(t, <-done)
+	})
+	t.Run("disabled for other process", func(t *testing.T) {
+		t.Setenv("WATCHDOG_USEC", "2000000")
+		t.Setenv("WATCHDOG_PID", strconv.Itoa(1<<30))
+
+		_, ok := systemd.WatchdogInterval()
+		require.False(t, ok)
+	})
+}
@@ -3,11 +3,15 @@ package let
 import (
 	"context"
 	"sync/atomic"
+	"time"
 )
 
 type Task interface {
 	// Run runs the task body and returns the error from the task body.
 	// After [Task.Stop] or [Task.Close], the task body is not run and [ErrClosed] is returned.
+	// The returned error is [ErrStopped] or [ErrForceClosed] if the Task distinguishes them.
+	// Tasks that cannot run anymore for other reasons, such as [Once] and [Limit]
+	// after their runs are used up, return [ErrClosed] itself.
 	Run(ctx context.Context) error
 	// Stop gracefully stops a task.
 	// Once Stop has been called on a task, it may not be reused;
@@ -22,6 +26,25 @@ type Task interface {
 	Wait() error
 }
 
+// TryRunner is a Task that can skip the Run if the Task is busy.
+// [Wrap], [Named], [Once], [Recover], [Breaker], and [Queue] forward TryRun to the given Task.
+type TryRunner interface {
+	Task
+
+	// TryRun is like [Task.Run] but returns [ErrBusy] immediately
+	// instead of blocking if the Task is already running.
+	TryRun(ctx context.Context) error
+}
+
+// TryRun calls [TryRunner.TryRun] of the Task if it is a [TryRunner],
+// or [Task.Run] otherwise.
+func TryRun(ctx context.Context, t Task) error {
+	if r, ok := t.(TryRunner); ok {
+		return r.TryRun(ctx)
+	}
+	return t.Run(ctx)
+}
+
 func Halt(t Task) error {
 	err := t.Close()
 	t.Wait()
@@ -34,7 +57,19 @@ type task struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	// stopping is canceled when the Task is requested to stop
+	// with the cause [ErrStopped] or [ErrForceClosed].
+	stopping context.Context
+	notify   context.CancelCauseFunc
+
+	// graceful is true if `ctx` is not canceled by Stop but by Close
+	// or after the `grace` period if it is positive.
+	graceful bool
+	grace    time.Duration
+	clock    Clock
+
 	stopped atomic.Bool
+	forced  atomic.Bool
 	closed  atomic.Bool
 
 	err   error
@@ -53,6 +88,7 @@ func NewWithContext(ctx context.Context, f func(ctx context.Context) error) Task
 		done:  make(chan struct{}),
 	}
 	t.ctx, t.cancel = context.WithCancel(ctx)
+	t.stopping, t.notify = context.WithCancelCause(t.ctx)
 	t.token <- struct{}{}
 
 	return t
@@ -82,19 +118,44 @@ func Nop() Task {
 func (t *task) Run(ctx context.Context) error {
 	// Check if the task is already stopped.
 	if t.stopped.Load() {
-		return ErrClosed
+		return t.closedErr()
 	}
 
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-t.done:
-		return ErrClosed
+		return t.closedErr()
 	case <-t.token:
 		// Previous task is done.
 	}
+
+	return t.run(ctx)
+}
+
+func (t *task) TryRun(ctx context.Context) error {
+	if t.stopped.Load() {
+		return t.closedErr()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case <-t.done:
+		return t.closedErr()
+	case <-t.token:
+	default:
+		return ErrBusy
+	}
+
+	return t.run(ctx)
+}
+
+// run runs the task body. The caller must hold the token.
+func (t *task) run(ctx context.Context) error {
 	if t.stopped.Load() {
-		return ErrClosed
+		return t.closedErr()
 	}
 
 	defer func() {
@@ -113,23 +174,51 @@ func (t *task) Run(ctx context.Context) error {
 	stop := context.AfterFunc(t.ctx, cancel)
 	defer stop()
 
+	ctx, notify := withStopping(ctx, t.stopping)
+	defer notify()
+
 	t.err = t.f(ctx)
 	return t.err
 }
 
+// closedErr returns the error of Run after the Task is stopped.
+func (t *task) closedErr() error {
+	if t.forced.Load() {
+		return ErrForceClosed
+	}
+	return ErrStopped
+}
+
 func (t *task) stop() {
 	if t.stopped.Swap(true) {
 		return
 	}
 
-	t.cancel()
+	t.notify(t.closedErr())
+	if !t.graceful {
+		t.cancel()
+		return
+	}
+	if t.grace <= 0 {
+		return
+	}
+
+	timer := t.clock.NewTimer(t.grace)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+			t.cancel()
+		case <-t.done:
+		}
+	}()
 }
 
 func (t *task) Stop(ctx context.Context) error {
 	t.stop()
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return stopErr(ctx)
 	case <-t.token:
 	case <-t.done:
 	}
@@ -151,7 +240,9 @@ func (t *task) close() {
 }
 
 func (t *task) Close() error {
+	t.forced.Store(true)
 	t.stop()
+	t.cancel()
 	t.close()
 	return nil
 }
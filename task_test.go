@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/lesomnus/let"
 	"github.com/stretchr/testify/require"
@@ -104,7 +105,8 @@ func TestNew(t *testing.T) {
 
 		task.Stop(t.Context())
 		err := task.Run(t.Context())
-		require.Equal(t, let.ErrClosed, err)
+		require.Equal(t, let.ErrStopped, err)
+		require.ErrorIs(t, err, let.ErrClosed)
 		require.Equal(t, 0, i)
 	})
 	t.Run("closed task does not run", func(t *testing.T) {
@@ -117,7 +119,111 @@ func TestNew(t *testing.T) {
 
 		task.Close()
 		err := task.Run(t.Context())
-		require.Equal(t, let.ErrClosed, err)
+		require.Equal(t, let.ErrForceClosed, err)
+		require.ErrorIs(t, err, let.ErrClosed)
 		require.Equal(t, 0, i)
 	})
+	t.Run("try run returns busy while running", func(t *testing.T) {
+		c := make(chan struct{})
+		task := let.New(func(ctx context.Context) error {
+			<-c
+			<-c
+			return nil
+		})
+		defer let.Halt(task)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			task.Run(t.Context())
+		}()
+
+		// Ensure the run started.
+		c <- struct{}{}
+
+		err := task.(let.TryRunner).TryRun(t.Context())
+		require.ErrorIs(t, err, let.ErrBusy)
+
+		c <- struct{}{}
+		<-done
+	})
+	t.Run("try run is forwarded by wrappers", func(t *testing.T) {
+		wrappers := map[string]func(t let.Task) let.Task{
+			"Wrap": func(t let.Task) let.Task {
+				return let.Wrap(t, func(ctx context.Context, next func(ctx context.Context) error) error {
+					return next(ctx)
+				})
+			},
+			"Named":   func(t let.Task) let.Task { return let.Named("foo", t) },
+			"Once":    let.Once,
+			"Recover": let.Recover,
+			"Breaker": func(t let.Task) let.Task { return let.Breaker(let.BreakerConfig{}, t) },
+		}
+		for name, wrap := range wrappers {
+			t.Run(name, func(t *testing.T) {
+				c := make(chan struct{})
+				base := let.New(func(ctx context.Context) error {
+					c <- struct{}{}
+					<-c
+					return nil
+				})
+				defer let.Halt(base)
+
+				task, ok := wrap(base).(let.TryRunner)
+				require.True(t, ok)
+
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					base.Run(t.Context())
+				}()
+
+				// Ensure the run started.
+				<-c
+
+				err := task.TryRun(t.Context())
+				require.ErrorIs(t, err, let.ErrBusy)
+
+				c <- struct{}{}
+				<-done
+			})
+		}
+	})
+	t.Run("try run runs if not busy", func(t *testing.T) {
+		i := 0
+		task := let.New(func(ctx context.Context) error {
+			i++
+			return nil
+		})
+		defer let.Halt(task)
+
+		err := task.(let.TryRunner).TryRun(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, 1, i)
+	})
+	t.Run("stop returns timeout if deadline exceeded", func(t *testing.T) {
+		c := make(chan struct{})
+		task := let.NewGraceful(0, func(ctx context.Context) error {
+			close(c)
+			<-ctx.Done()
+			return nil
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			task.Run(t.Context())
+		}()
+		<-c
+
+		ctx, cancel := context.WithDeadline(t.Context(), time.Now())
+		defer cancel()
+
+		err := task.Stop(ctx)
+		require.ErrorIs(t, err, let.ErrTimeout)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		task.Close()
+		<-done
+	})
 }
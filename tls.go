@@ -0,0 +1,166 @@
+package let
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// HttpListenAndServeTLS creates a Task that runs [http.Server.ListenAndServeTLS].
+func HttpListenAndServeTLS(s *http.Server, certFile string, keyFile string) HttpServeTask {
+	return newHttpServe(s, func(ctx context.Context) error {
+		return s.ListenAndServeTLS(certFile, keyFile)
+	})
+}
+
+// HttpServeTLS creates a Task that runs [http.Server.ServeTLS] with the given listener.
+func HttpServeTLS(s *http.Server, l net.Listener, certFile string, keyFile string) HttpServeTask {
+	return newHttpServe(s, func(ctx context.Context) error {
+		return s.ServeTLS(l, certFile, keyFile)
+	})
+}
+
+// HttpListenAndServeTLSWithReloader is like [HttpListenAndServeTLS] but the certificate
+// is provided by the given CertReloader, which watches the files while the Task runs.
+func HttpListenAndServeTLSWithReloader(s *http.Server, r *CertReloader) HttpServeTask {
+	r.apply(s)
+	return newHttpServe(s, func(ctx context.Context) error {
+		defer r.watch(ctx)()
+		return s.ListenAndServeTLS("", "")
+	})
+}
+
+// HttpServeTLSWithReloader is like [HttpServeTLS] but the certificate
+// is provided by the given CertReloader, which watches the files while the Task runs.
+func HttpServeTLSWithReloader(s *http.Server, l net.Listener, r *CertReloader) HttpServeTask {
+	r.apply(s)
+	return newHttpServe(s, func(ctx context.Context) error {
+		defer r.watch(ctx)()
+		return s.ServeTLS(l, "", "")
+	})
+}
+
+// CertReloader provides a certificate loaded from files and
+// reloads it when the files are modified.
+type CertReloader struct {
+	cert_file string
+	key_file  string
+
+	clock    Clock
+	interval time.Duration
+
+	m        sync.RWMutex
+	cert     *tls.Certificate
+	cert_mod time.Time
+	key_mod  time.Time
+}
+
+// NewCertReloader loads a certificate from the given files and
+// creates a CertReloader that checks the files for modification every `interval`.
+func NewCertReloader(certFile string, keyFile string, interval time.Duration) (*CertReloader, error) {
+	return NewCertReloaderWithClock(RealClock, certFile, keyFile, interval)
+}
+
+// NewCertReloaderWithClock is like [NewCertReloader] but the interval is measured by the given Clock.
+func NewCertReloaderWithClock(c Clock, certFile string, keyFile string, interval time.Duration) (*CertReloader, error) {
+	if interval <= 0 {
+		return nil, errors.New("interval must be larger than 0")
+	}
+
+	r := &CertReloader{
+		cert_file: certFile,
+		key_file:  keyFile,
+
+		clock:    clockOr(c),
+		interval: interval,
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate returns the current certificate.
+// It can be used as [tls.Config.GetCertificate].
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	return r.cert, nil
+}
+
+// Reload loads the certificate if the files are modified since the last load
+// and reports whether the certificate is reloaded.
+// The current certificate is kept if it fails to load.
+func (r *CertReloader) Reload() (bool, error) {
+	cert_stat, err := os.Stat(r.cert_file)
+	if err != nil {
+		return false, err
+	}
+	key_stat, err := os.Stat(r.key_file)
+	if err != nil {
+		return false, err
+	}
+
+	r.m.RLock()
+	modified := r.cert == nil ||
+		!cert_stat.ModTime().Equal(r.cert_mod) ||
+		!key_stat.ModTime().Equal(r.key_mod)
+	r.m.RUnlock()
+	if !modified {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cert_file, r.key_file)
+	if err != nil {
+		return false, err
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.cert = &cert
+	r.cert_mod = cert_stat.ModTime()
+	r.key_mod = key_stat.ModTime()
+
+	return true, nil
+}
+
+func (r *CertReloader) apply(s *http.Server) {
+	if s.TLSConfig == nil {
+		s.TLSConfig = &tls.Config{}
+	}
+	s.TLSConfig.GetCertificate = r.GetCertificate
+}
+
+// watch reloads the certificate every interval until the returned function is called
+// or the context is done.
+func (r *CertReloader) watch(ctx context.Context) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	goTask(ctx, r, func(ctx context.Context) {
+		defer close(done)
+
+		ticker := r.clock.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				// Keep serving the current certificate on failure.
+				r.Reload()
+			}
+		}
+	})
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
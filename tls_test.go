@@ -0,0 +1,162 @@
+package let_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lesomnus/let"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCert(t *testing.T, dir string, serial int64, mod time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	key_der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	cert_file := filepath.Join(dir, "cert.pem")
+	key_file := filepath.Join(dir, "key.pem")
+	err = os.WriteFile(cert_file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	require.NoError(t, err)
+	err = os.WriteFile(key_file, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key_der}), 0o600)
+	require.NoError(t, err)
+
+	// Modification time is set explicitly since the resolution of the file system may be coarse.
+	require.NoError(t, os.Chtimes(cert_file, mod, mod))
+	require.NoError(t, os.Chtimes(key_file, mod, mod))
+
+	return cert_file, key_file
+}
+
+func TestHttpServeTLS(t *testing.T) {
+	serial := func(t *testing.T, addr string) int64 {
+		conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	t.Run("serves TLS", func(t *testing.T) {
+		cert_file, key_file := writeCert(t, t.TempDir(), 1, time.Now())
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		task := let.HttpServeTLS(&http.Server{}, l, cert_file, key_file)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		require.Equal(t, int64(1), serial(t, l.Addr().String()))
+
+		task.Stop(t.Context())
+		require.ErrorIs(t, <-done, http.ErrServerClosed)
+	})
+	t.Run("certificate is reloaded", func(t *testing.T) {
+		dir := t.TempDir()
+		t0 := time.Now().Add(-time.Minute)
+		cert_file, key_file := writeCert(t, dir, 1, t0)
+
+		c := let.NewFakeClock(time.Time{})
+		r, err := let.NewCertReloaderWithClock(c, cert_file, key_file, time.Second)
+		require.NoError(t, err)
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		task := let.HttpServeTLSWithReloader(&http.Server{}, l, r)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		require.Equal(t, int64(1), serial(t, l.Addr().String()))
+
+		writeCert(t, dir, 2, t0.Add(time.Second))
+
+		// Ensure the watcher is waiting.
+		c.BlockUntil(t.Context(), 1)
+		c.Advance(time.Second)
+
+		require.Eventually(t, func() bool {
+			return serial(t, l.Addr().String()) == 2
+		}, time.Second, 10*time.Millisecond)
+
+		task.Stop(t.Context())
+		require.ErrorIs(t, <-done, http.ErrServerClosed)
+	})
+	t.Run("current certificate is kept on reload failure", func(t *testing.T) {
+		dir := t.TempDir()
+		cert_file, key_file := writeCert(t, dir, 1, time.Now().Add(-time.Minute))
+
+		r, err := let.NewCertReloader(cert_file, key_file, time.Hour)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(cert_file, []byte("invalid"), 0o600))
+
+		ok, err := r.Reload()
+		require.Error(t, err)
+		require.False(t, ok)
+
+		cert, err := r.GetCertificate(nil)
+		require.NoError(t, err)
+		require.NotNil(t, cert)
+	})
+	t.Run("interval must be positive", func(t *testing.T) {
+		dir := t.TempDir()
+		cert_file, key_file := writeCert(t, dir, 1, time.Now().Add(-time.Minute))
+
+		_, err := let.NewCertReloader(cert_file, key_file, 0)
+		require.Error(t, err)
+	})
+	t.Run("nil clock defaults to the real clock", func(t *testing.T) {
+		dir := t.TempDir()
+		cert_file, key_file := writeCert(t, dir, 1, time.Now().Add(-time.Minute))
+
+		r, err := let.NewCertReloaderWithClock(nil, cert_file, key_file, time.Hour)
+		require.NoError(t, err)
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		task := let.HttpServeTLSWithReloader(&http.Server{}, l, r)
+		defer let.Halt(task)
+
+		done := make(chan error)
+		go func() {
+			done <- task.Run(t.Context())
+		}()
+
+		require.Equal(t, int64(1), serial(t, l.Addr().String()))
+
+		task.Stop(t.Context())
+		require.ErrorIs(t, <-done, http.ErrServerClosed)
+	})
+}
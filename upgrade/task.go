@@ -0,0 +1,51 @@
+//go:build unix
+
+package upgrade
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lesomnus/let"
+)
+
+// Task creates a Task that upgrades the process when [Upgrader.Trigger] is called
+// or SIGUSR2 is received, and stops the given Task after the upgrade succeeds.
+// Failed upgrades are reported to `onError`, which may be nil, and the process keeps serving.
+// The Task returns after the given Task is stopped.
+func (u *Upgrader) Task(t let.Task, onError func(err error)) let.Task {
+	return let.New(func(ctx context.Context) error {
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGUSR2)
+		defer signal.Stop(sigs)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-sigs:
+			case <-u.triggered:
+			}
+
+			if err := u.Upgrade(ctx); err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+
+			return t.Stop(ctx)
+		}
+	})
+}
+
+// Trigger requests the Task created by [Upgrader.Task] to upgrade the process.
+func (u *Upgrader) Trigger() {
+	select {
+	case u.triggered <- struct{}{}:
+	default:
+		// Already triggered.
+	}
+}
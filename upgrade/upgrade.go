@@ -0,0 +1,315 @@
+//go:build unix
+
+// Package upgrade restarts a process without dropping connections
+// by passing its listeners to a new instance of the process.
+//
+// The listeners are created by [Upgrader.Listen] so they can be inherited.
+// On upgrade, the process re-executes itself with the listeners and
+// waits for the new process to call [Upgrader.Ready].
+// Then the old process stops its Tasks, which stops accepting connections
+// and drains in-flight ones, while the new process accepts connections
+// on the same sockets.
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+const (
+	envFds   = "LET_UPGRADE_FDS"
+	envReady = "LET_UPGRADE_READY_FD"
+
+	// First file descriptor of [exec.Cmd.ExtraFiles].
+	fds_start = 3
+)
+
+var (
+	ErrNotChild       = errors.New("not started by an upgrade")
+	ErrUpgrading      = errors.New("upgrade in progress")
+	ErrChildExited    = errors.New("child exited before ready")
+	ErrUnknownNetwork = errors.New("listener of unknown network")
+)
+
+type key struct {
+	network string
+	addr    string
+}
+
+func (k key) String() string {
+	return k.network + ":" + k.addr
+}
+
+// Upgrader passes listeners to a new instance of the process.
+type Upgrader struct {
+	// Command creates a command that starts the new instance of the process.
+	// Defaults to the executable of the current process with the same arguments.
+	Command func() *exec.Cmd
+
+	m         sync.Mutex
+	inherited map[key]*os.File
+	listeners map[key]syscall.Conn
+	ready     *os.File
+	upgrading bool
+	triggered chan struct{}
+}
+
+// New creates an Upgrader that takes over the listeners passed by the parent process, if any.
+func New() (*Upgrader, error) {
+	u := &Upgrader{
+		inherited: map[key]*os.File{},
+		listeners: map[key]syscall.Conn{},
+		triggered: make(chan struct{}, 1),
+	}
+
+	if v := os.Getenv(envFds); v != "" {
+		for i, s := range strings.Split(v, ";") {
+			network, addr, ok := strings.Cut(s, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid %s: %q", envFds, s)
+			}
+			k := key{network, addr}
+			u.inherited[k] = os.NewFile(uintptr(fds_start+i), k.String())
+		}
+	}
+	if v := os.Getenv(envReady); v != "" {
+		fd, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", envReady, err)
+		}
+		u.ready = os.NewFile(uintptr(fd), "ready")
+	}
+
+	// Children of this process must not see them.
+	os.Unsetenv(envFds)
+	os.Unsetenv(envReady)
+
+	return u, nil
+}
+
+// Listen returns the listener inherited from the parent process for the given network and address.
+// If there is none, it creates a new listener.
+// The network must be one of "tcp", "tcp4", "tcp6", "unix" or "unixpacket".
+// Note that the listener is looked up by the given address, not by the bound address,
+// so the same address must be given to inherit the listener across upgrades.
+func (u *Upgrader) Listen(network string, addr string) (net.Listener, error) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	k := key{network, addr}
+	if _, ok := u.listeners[k]; ok {
+		return nil, fmt.Errorf("listen %s: already listening", k)
+	}
+
+	if f, ok := u.inherited[k]; ok {
+		delete(u.inherited, k)
+
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("inherit %s: %w", k, err)
+		}
+
+		return u.track(k, l)
+	}
+
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.track(k, l)
+}
+
+func (u *Upgrader) track(k key, l net.Listener) (net.Listener, error) {
+	c, ok := l.(syscall.Conn)
+	if !ok {
+		l.Close()
+		return nil, ErrUnknownNetwork
+	}
+	if ul, ok := l.(*net.UnixListener); ok {
+		// The socket file must be left for the new process.
+		ul.SetUnlinkOnClose(false)
+	}
+
+	u.listeners[k] = c
+	return l, nil
+}
+
+// dup duplicates the file descriptor of the given listener.
+// Unlike [net.TCPListener.File], the returned file does not put the shared
+// file description into blocking mode when it is passed to a child process,
+// which would block Accept and Close of the listener of this process.
+func dup(c syscall.Conn) (*os.File, error) {
+	raw, err := c.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var fd int
+	var dup_err error
+	err = raw.Control(func(v uintptr) {
+		fd, dup_err = syscall.Dup(int(v))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dup_err != nil {
+		return nil, os.NewSyscallError("dup", dup_err)
+	}
+
+	return os.NewFile(uintptr(fd), "listener"), nil
+}
+
+// Ready notifies the parent process that this process is ready to serve
+// so the parent can stop.
+// It returns [ErrNotChild] if this process is not started by an upgrade.
+// The listeners inherited but not taken by [Upgrader.Listen] are closed.
+func (u *Upgrader) Ready() error {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	for k, f := range u.inherited {
+		f.Close()
+		delete(u.inherited, k)
+	}
+
+	if u.ready == nil {
+		return ErrNotChild
+	}
+	defer func() {
+		u.ready.Close()
+		u.ready = nil
+	}()
+
+	_, err := u.ready.Write([]byte{1})
+	return err
+}
+
+func (u *Upgrader) command() *exec.Cmd {
+	if u.Command != nil {
+		return u.Command()
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		exe = os.Args[0]
+	}
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+func (u *Upgrader) begin() ([]key, []*os.File, error) {
+	u.m.Lock()
+	defer u.m.Unlock()
+	if u.upgrading {
+		return nil, nil, ErrUpgrading
+	}
+	u.upgrading = true
+
+	ks := make([]key, 0, len(u.listeners))
+	fs := make([]*os.File, 0, len(u.listeners))
+	for k, c := range u.listeners {
+		f, err := dup(c)
+		if err != nil {
+			for _, f := range fs {
+				f.Close()
+			}
+			u.upgrading = false
+			return nil, nil, fmt.Errorf("dup %s: %w", k, err)
+		}
+
+		ks = append(ks, k)
+		fs = append(fs, f)
+	}
+
+	return ks, fs, nil
+}
+
+func (u *Upgrader) end() {
+	u.m.Lock()
+	defer u.m.Unlock()
+	u.upgrading = false
+}
+
+// Upgrade starts a new instance of the process with the listeners
+// and blocks until the new process calls [Upgrader.Ready].
+// If the new process exits before it is ready or the context is done,
+// the new process is killed and an error is returned.
+// The caller is responsible to stop serving after a successful upgrade.
+func (u *Upgrader) Upgrade(ctx context.Context) error {
+	ks, fs, err := u.begin()
+	if err != nil {
+		return err
+	}
+	defer u.end()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	vs := make([]string, len(ks))
+	for i, k := range ks {
+		vs[i] = k.String()
+	}
+
+	cmd := u.command()
+	cmd.ExtraFiles = append(fs, w)
+	cmd.Env = append(cmd.Environ(),
+		envFds+"="+strings.Join(vs, ";"),
+		envReady+"="+strconv.Itoa(fds_start+len(fs)),
+	)
+
+	err = cmd.Start()
+	w.Close()
+	for _, f := range fs {
+		f.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		cmd.Wait()
+	}()
+
+	ready := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		if err == io.EOF {
+			// The pipe is closed without ready as the child exited.
+			err = ErrChildExited
+		}
+		ready <- err
+	}()
+
+	select {
+	case err := <-ready:
+		if err == nil {
+			return nil
+		}
+		cmd.Process.Kill()
+		return err
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		<-exited
+		return ctx.Err()
+	}
+}
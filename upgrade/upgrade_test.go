@@ -0,0 +1,148 @@
+//go:build unix
+
+package upgrade_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/lesomnus/let"
+	"github.com/lesomnus/let/upgrade"
+	"github.com/stretchr/testify/require"
+)
+
+const addr = "127.0.0.1:0"
+
+// TestHelperProcess is run by the upgrade as the new instance of the process.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("LET_UPGRADE_HELPER") == "" {
+		t.Skip("helper process")
+	}
+
+	u, err := upgrade.New()
+	require.NoError(t, err)
+
+	l, err := u.Listen("tcp", addr)
+	require.NoError(t, err)
+	defer l.Close()
+
+	if os.Getenv("LET_UPGRADE_HELPER") == "fail" {
+		os.Exit(1)
+	}
+
+	err = u.Ready()
+	require.NoError(t, err)
+
+	// Serve a connection and exit.
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	conn.Write([]byte("child\n"))
+}
+
+func helper(mode string) func() *exec.Cmd {
+	return func() *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+		cmd.Env = append(os.Environ(), "LET_UPGRADE_HELPER="+mode)
+		return cmd
+	}
+}
+
+func serve(t *testing.T, l net.Listener, name string) let.Task {
+	task := let.ServeConn(l, func(ctx context.Context, conn net.Conn) error {
+		_, err := conn.Write([]byte(name + "\n"))
+		return err
+	})
+	go task.Run(t.Context())
+	return task
+}
+
+func dial(t *testing.T, addr string) string {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	v, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	return v
+}
+
+func TestUpgrade(t *testing.T) {
+	t.Run("listener is handed over", func(t *testing.T) {
+		u, err := upgrade.New()
+		require.NoError(t, err)
+		u.Command = helper("serve")
+
+		l, err := u.Listen("tcp", addr)
+		require.NoError(t, err)
+
+		task := serve(t, l, "parent")
+		defer let.Halt(task)
+
+		bound := l.Addr().String()
+		require.Equal(t, "parent\n", dial(t, bound))
+
+		err = u.Upgrade(t.Context())
+		require.NoError(t, err)
+
+		err = task.Stop(t.Context())
+		require.NoError(t, err)
+
+		// The socket is still open by the child.
+		require.Equal(t, "child\n", dial(t, bound))
+	})
+	t.Run("child exits before ready", func(t *testing.T) {
+		u, err := upgrade.New()
+		require.NoError(t, err)
+		u.Command = helper("fail")
+
+		l, err := u.Listen("tcp", addr)
+		require.NoError(t, err)
+
+		task := serve(t, l, "parent")
+		defer let.Halt(task)
+
+		err = u.Upgrade(t.Context())
+		require.ErrorIs(t, err, upgrade.ErrChildExited)
+
+		// Parent keeps serving.
+		require.Equal(t, "parent\n", dial(t, l.Addr().String()))
+	})
+	t.Run("task stops the given task after upgrade", func(t *testing.T) {
+		u, err := upgrade.New()
+		require.NoError(t, err)
+		u.Command = helper("serve")
+
+		l, err := u.Listen("tcp", addr)
+		require.NoError(t, err)
+
+		task := serve(t, l, "parent")
+		defer let.Halt(task)
+
+		w := u.Task(task, func(err error) {
+			t.Error(err)
+		})
+		defer let.Halt(w)
+
+		done := make(chan error)
+		go func() {
+			done <- w.Run(t.Context())
+		}()
+
+		u.Trigger()
+		require.NoError(t, <-done)
+		require.Equal(t, "child\n", dial(t, l.Addr().String()))
+	})
+	t.Run("ready without parent", func(t *testing.T) {
+		u, err := upgrade.New()
+		require.NoError(t, err)
+
+		err = u.Ready()
+		require.ErrorIs(t, err, upgrade.ErrNotChild)
+	})
+}
@@ -83,7 +83,7 @@ func (r *worker) start(ctx context.Context) {
 
 func (r *worker) run(t Task, ctx context.Context) {
 	r.wg.Add(1)
-	go func() {
+	goTask(ctx, t, func(ctx context.Context) {
 		defer r.wg.Done()
 
 		t.Run(ctx)
@@ -96,7 +96,7 @@ func (r *worker) run(t Task, ctx context.Context) {
 
 		Halt(t)
 		delete(r.tasks, t)
-	}()
+	})
 }
 
 func (r *worker) Run(ctx context.Context) error {
@@ -130,12 +130,12 @@ func (r *worker) stopTasks() {
 
 func (r *worker) Stop(ctx context.Context) error {
 	if !r.stop() {
-		go r.stopTasks()
+		goTask(ctx, r, func(context.Context) { r.stopTasks() })
 	}
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return stopErr(ctx)
 	case <-r.stop_done:
 		return nil
 	}
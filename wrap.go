@@ -9,7 +9,8 @@ type wrapped struct {
 	base Task
 	f    func(ctx context.Context, next func(ctx context.Context) error) error
 
-	closed atomic.Bool
+	stopped atomic.Bool
+	forced  atomic.Bool
 }
 
 // Wrap intercepts the Run of the given Task.
@@ -18,24 +19,41 @@ func Wrap(t Task, f func(ctx context.Context, next func(ctx context.Context) err
 	return &wrapped{base: t, f: f}
 }
 
+func (t *wrapped) closedErr() error {
+	if t.forced.Load() {
+		return ErrForceClosed
+	}
+	return ErrStopped
+}
+
 func (t *wrapped) Run(ctx context.Context) error {
-	if t.closed.Load() {
-		return ErrClosed
+	if t.stopped.Load() {
+		return t.closedErr()
 	}
 	return t.f(ctx, t.base.Run)
 }
 
+func (t *wrapped) TryRun(ctx context.Context) error {
+	if t.stopped.Load() {
+		return t.closedErr()
+	}
+	return t.f(ctx, func(ctx context.Context) error {
+		return TryRun(ctx, t.base)
+	})
+}
+
 func (t *wrapped) Stop(ctx context.Context) error {
-	t.closed.Store(true)
+	t.stopped.Store(true)
 	return t.base.Stop(ctx)
 }
 
 func (t *wrapped) Close() error {
-	t.closed.Store(true)
+	t.forced.Store(true)
+	t.stopped.Store(true)
 	return t.base.Close()
 }
 
 func (t *wrapped) Wait() error {
-	t.closed.Store(true)
+	t.stopped.Store(true)
 	return t.base.Wait()
 }
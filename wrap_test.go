@@ -46,9 +46,22 @@ func TestWrap(t *testing.T) {
 
 		task.Run(t.Context())
 		task.Stop(t.Context())
-		task.Run(t.Context())
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrStopped)
 		require.Equal(t, 1, v)
 	})
+	t.Run("close prevents the next run", func(t *testing.T) {
+		task := let.Wrap(
+			let.Nop(),
+			func(ctx context.Context, next func(ctx context.Context) error) error {
+				return next(ctx)
+			},
+		)
+		task.Close()
+
+		err := task.Run(t.Context())
+		require.ErrorIs(t, err, let.ErrForceClosed)
+	})
 	t.Run("stop is propagated", func(t *testing.T) {
 		v := ""
 		c := make(chan struct{})